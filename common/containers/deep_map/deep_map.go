@@ -49,3 +49,30 @@ func (y *DeepMap) IsList() bool {
 func (y *DeepMap) GetScalar() string {
 	return y.node.Value
 }
+
+// GetList returns scalar values of the current YAML list, a single scalar is treated as one element list.
+func (y *DeepMap) GetList() []string {
+	if y.IsScalar() {
+		return []string{y.node.Value}
+	}
+	if !y.IsList() {
+		return nil
+	}
+	values := make([]string, 0, len(y.node.Content))
+	for _, v := range y.node.Content {
+		values = append(values, v.Value)
+	}
+	return values
+}
+
+// Keys returns keys of the current YAML map in the order they are declared.
+func (y *DeepMap) Keys() []string {
+	if !y.IsMap() {
+		return nil
+	}
+	keys := make([]string, 0, len(y.node.Content)/2)
+	for i := 0; i < len(y.node.Content); i += 2 {
+		keys = append(keys, y.node.Content[i].Value)
+	}
+	return keys
+}
@@ -0,0 +1,49 @@
+package digest_job_locales
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+
+	"common/data"
+	"common/data/drivers/postgres"
+	"common/data/model"
+	"common/data/queriers"
+)
+
+type digestJobLocales struct {
+	log *logrus.Entry
+	ext sqlx.ExtContext
+
+	expr sq.Sqlizer
+
+	postgres.Inserter[model.DigestJobLocale]
+	postgres.Selector[model.DigestJobLocale]
+}
+
+func New(ext sqlx.ExtContext, log *logrus.Entry) queriers.DigestJobLocalesProvider {
+	var entity model.DigestJobLocale
+	digestJobLocalesColumns := model.PrependTableName(entity.TableName(), model.Columns(entity, false))
+	return &digestJobLocales{
+		log: log.WithField("provider", "digest_job_locales"),
+		ext: ext,
+
+		Inserter: postgres.NewInserter[model.DigestJobLocale](ext, log),
+		Selector: postgres.NewSelector[model.DigestJobLocale](ext, log, digestJobLocalesColumns),
+
+		expr: data.BasicSqlizer,
+	}
+}
+
+func (d digestJobLocales) ByJobIDs(ids []uuid.UUID) queriers.DigestJobLocalesProvider {
+	d.expr = sq.And{d.expr, sq.Eq{"digest_job_locales.job_id": ids}}
+	return d
+}
+
+func (d digestJobLocales) Select(ctx context.Context) ([]model.DigestJobLocale, error) {
+	d.Selector = d.Selector.WithExpr(d.expr)
+	return d.Selector.Select(ctx)
+}
@@ -0,0 +1,65 @@
+package digest_jobs
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+
+	"common"
+	"common/convert"
+	"common/data"
+	"common/data/drivers/postgres"
+	"common/data/model"
+	"common/data/queriers"
+)
+
+type digestJobs struct {
+	log *logrus.Entry
+	ext sqlx.ExtContext
+
+	expr sq.Sqlizer
+
+	postgres.Inserter[model.DigestJob]
+	postgres.Selector[model.DigestJob]
+	postgres.Updater[model.UpdateDigestJobParams, model.DigestJob]
+}
+
+func New(ext sqlx.ExtContext, log *logrus.Entry) queriers.DigestJobsProvider {
+	var entity model.DigestJob
+	digestJobsColumns := model.PrependTableName(entity.TableName(), model.Columns(entity, false))
+	return &digestJobs{
+		log: log.WithField("provider", "digest_jobs"),
+		ext: ext,
+
+		Inserter: postgres.NewInserter[model.DigestJob](ext, log),
+		Selector: postgres.NewSelector[model.DigestJob](ext, log, digestJobsColumns),
+		Updater:  postgres.NewUpdater[model.UpdateDigestJobParams, model.DigestJob](ext, log),
+
+		expr: data.BasicSqlizer,
+	}
+}
+
+func (d digestJobs) ByIDs(ids []uuid.UUID) queriers.DigestJobsProvider {
+	d.expr = sq.And{d.expr, sq.Eq{"digest_jobs.id": ids}}
+	return d
+}
+
+func (d digestJobs) ByIdempotencyKeys(keys ...string) queriers.DigestJobsProvider {
+	d.expr = sq.And{d.expr, sq.Eq{"digest_jobs.idempotency_key": keys}}
+	return d
+}
+
+func (d digestJobs) Select(ctx context.Context) ([]model.DigestJob, error) {
+	d.Selector = d.Selector.WithExpr(d.expr)
+	return d.Selector.Select(ctx)
+}
+
+func (d digestJobs) Update(ctx context.Context, job model.UpdateDigestJobParams) ([]model.DigestJob, error) {
+	d.Updater = d.Updater.WithExpr(d.expr)
+
+	job.UpdatedAt = convert.ToPtr(common.CurrentTimestamp())
+	return d.Updater.Update(ctx, job)
+}
@@ -59,6 +59,11 @@ func (n news) ByIDs(ids []uuid.UUID) queriers.NewsProvider {
 	return n
 }
 
+func (n news) ByDigestIDs(ids []uuid.UUID) queriers.NewsProvider {
+	n.expr = sq.And{n.expr, sq.Eq{"news.digest_id": ids}}
+	return n
+}
+
 func (n news) ByCoins(codes []string) queriers.NewsProvider {
 	sql, args, _ := sq.Eq{"news_coins.code": codes}.ToSql()
 	n.Selector = n.Selector.Join("news_coins", sql, args...)
@@ -45,6 +45,11 @@ func (w rawNews) ByIDs(ids []uuid.UUID) queriers.RawNewsProvider {
 	return w
 }
 
+func (w rawNews) ByTitleIDs(ids []uuid.UUID) queriers.RawNewsProvider {
+	w.expr = sq.And{w.expr, sq.Eq{"raw_news.title_id": ids}}
+	return w
+}
+
 func (w rawNews) Limit(l uint64) queriers.RawNewsProvider {
 	w.Selector = w.Selector.Limit(l)
 	return w
@@ -0,0 +1,55 @@
+package stories
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+
+	"common/data"
+	"common/data/drivers/postgres"
+	"common/data/model"
+	"common/data/queriers"
+)
+
+type stories struct {
+	log *logrus.Entry
+	ext sqlx.ExtContext
+
+	expr sq.Sqlizer
+
+	postgres.Inserter[model.Story]
+	postgres.Selector[model.Story]
+}
+
+func New(ext sqlx.ExtContext, log *logrus.Entry) queriers.StoriesProvider {
+	var entity model.Story
+	storiesColumns := model.PrependTableName(entity.TableName(), model.Columns(entity, false))
+	return &stories{
+		log: log.WithField("provider", "stories"),
+		ext: ext,
+
+		Inserter: postgres.NewInserter[model.Story](ext, log),
+		Selector: postgres.NewSelector[model.Story](ext, log, storiesColumns),
+
+		expr: data.BasicSqlizer,
+	}
+}
+
+func (s stories) ByIDs(ids []uuid.UUID) queriers.StoriesProvider {
+	s.expr = sq.And{s.expr, sq.Eq{"stories.id": ids}}
+	return s
+}
+
+func (s stories) CreatedAfter(t time.Time) queriers.StoriesProvider {
+	s.expr = sq.And{s.expr, sq.Gt{"stories.created_at": t}}
+	return s
+}
+
+func (s stories) Select(ctx context.Context) ([]model.Story, error) {
+	s.Selector = s.Selector.WithExpr(s.expr)
+	return s.Selector.Select(ctx)
+}
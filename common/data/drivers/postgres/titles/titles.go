@@ -2,6 +2,7 @@ package titles
 
 import (
 	"context"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
@@ -26,6 +27,7 @@ type titles struct {
 	postgres.Inserter[model.Title]
 	postgres.Selector[model.Title]
 	postgres.Updater[model.UpdateTitleParams, model.Title]
+	postgres.Remover[model.Title]
 }
 
 func New(ext sqlx.ExtContext, log *logrus.Entry) queriers.TitlesProvider {
@@ -38,16 +40,42 @@ func New(ext sqlx.ExtContext, log *logrus.Entry) queriers.TitlesProvider {
 		Inserter: postgres.NewInserter[model.Title](ext, log),
 		Selector: postgres.NewSelector[model.Title](ext, log, titlesColumns),
 		Updater:  postgres.NewUpdater[model.UpdateTitleParams, model.Title](ext, log),
+		Remover:  postgres.NewRemover[model.Title](ext, log),
 
 		expr: data.BasicSqlizer,
 	}
 }
 
+func (t titles) ByHashes(hashes []string) queriers.TitlesProvider {
+	t.expr = sq.And{t.expr, sq.Eq{"titles.hash": hashes}}
+	return t
+}
+
+func (t titles) ByURLs(urls []string) queriers.TitlesProvider {
+	t.expr = sq.And{t.expr, sq.Eq{"titles.url": urls}}
+	return t
+}
+
 func (t titles) ByStatus(status ...string) queriers.TitlesProvider {
 	t.expr = sq.And{t.expr, sq.Eq{"titles.status": status}}
 	return t
 }
 
+func (t titles) ByStoryIDs(ids []uuid.UUID) queriers.TitlesProvider {
+	t.expr = sq.And{t.expr, sq.Eq{"titles.story_id": ids}}
+	return t
+}
+
+func (t titles) DueAt(at time.Time) queriers.TitlesProvider {
+	t.expr = sq.And{t.expr, sq.Or{sq.Eq{"titles.next_attempt_at": nil}, sq.LtOrEq{"titles.next_attempt_at": at}}}
+	return t
+}
+
+func (t titles) After(id uuid.UUID) queriers.TitlesProvider {
+	t.expr = sq.And{t.expr, sq.Gt{"titles.id": id}}
+	return t
+}
+
 func (t titles) ByIDs(ids []uuid.UUID) queriers.TitlesProvider {
 	t.expr = sq.And{t.expr, sq.Eq{"titles.id": ids}}
 	return t
@@ -64,7 +92,8 @@ func (t titles) InsertUniqueBatch(ctx context.Context, entities []model.Title) e
 	}
 
 	sql := `
-		INSERT INTO titles (title, summary, hash, url, release_date, status) VALUES (:title, :summary, :hash, :url, :release_date, :status) 
+		INSERT INTO titles (title, summary, hash, url, release_date, status, source, kind)
+			VALUES (:title, :summary, :hash, :url, :release_date, :status, :source, COALESCE(:kind, 'news'))
         	ON CONFLICT (hash) DO NOTHING RETURNING *`
 
 	rows, err := sqlx.NamedQueryContext(ctx, t.ext, t.ext.Rebind(sql), entities)
@@ -72,17 +101,74 @@ func (t titles) InsertUniqueBatch(ctx context.Context, entities []model.Title) e
 		return errors.Wrap(err, "failed to insert entity into table: titles")
 	}
 
-	idx := 0
+	// duplicates are skipped, so returned rows are matched back to the entities by hash
+	hashIdx := make(map[string]int, len(entities))
+	for i, e := range entities {
+		hashIdx[convert.FromPtr(e.Hash)] = i
+	}
+
 	for rows.Next() {
-		err := rows.StructScan(&entities[idx])
-		if err != nil {
+		var inserted model.Title
+		if err := rows.StructScan(&inserted); err != nil {
 			return errors.Wrap(err, "failed to scan entity")
 		}
-		idx++
+
+		if idx, ok := hashIdx[convert.FromPtr(inserted.Hash)]; ok {
+			inserted.Coins = entities[idx].Coins
+			entities[idx] = inserted
+		}
 	}
 	return nil
 }
 
+func (t titles) Claim(ctx context.Context, limit uint64, leaseUntil time.Time) ([]model.Title, error) {
+	claimable := sq.Select("titles.id").
+		From(model.TITLES).
+		Where(t.expr).
+		OrderBy("titles.id").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query := sq.Update(model.TITLES).
+		Set("status", model.StatusProcessing).
+		Set("next_attempt_at", leaseUntil).
+		Set("updated_at", common.CurrentTimestamp()).
+		Where(sq.Expr("titles.id IN (?)", claimable)).
+		Suffix("RETURNING *")
+
+	t.log.Debug(sq.DebugSqlizer(query))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build sql claim query")
+	}
+
+	rows, err := t.ext.QueryxContext(ctx, t.ext.Rebind(sql), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim titles")
+	}
+	defer rows.Close()
+
+	claimed := make([]model.Title, 0, limit)
+	for rows.Next() {
+		var title model.Title
+		if err := rows.StructScan(&title); err != nil {
+			return nil, errors.Wrap(err, "failed to scan claimed title")
+		}
+		claimed = append(claimed, title)
+	}
+
+	if len(claimed) == 0 {
+		return nil, data.ErrNotFound
+	}
+	return claimed, nil
+}
+
+func (t titles) Remove(ctx context.Context, entity model.Title) error {
+	t.Remover = t.Remover.WithExpr(t.expr)
+	return t.Remover.Remove(ctx, entity)
+}
+
 func (t titles) Update(ctx context.Context, title model.UpdateTitleParams) ([]model.Title, error) {
 	t.Updater = t.Updater.WithExpr(t.expr)
 
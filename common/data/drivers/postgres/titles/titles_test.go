@@ -0,0 +1,131 @@
+//go:build integration
+
+package titles
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"common/convert"
+	"common/data/drivers"
+	"common/data/model"
+)
+
+func InsertUniqueBatchKind(t *testing.T, log *logrus.Entry, db *sqlx.DB) {
+	testTitles := New(db, log)
+
+	ctx := context.Background()
+
+	inTitles := []model.Title{
+		{
+			Title:  convert.ToPtr("Bitcoin hits a new high"),
+			Hash:   convert.ToPtr("kind-news"),
+			URL:    convert.ToPtr("https://example.com/news"),
+			Status: convert.ToPtr(model.StatusPending),
+			Source: convert.ToPtr("example"),
+		},
+		{
+			Title:  convert.ToPtr("What do you think about the new high?"),
+			Hash:   convert.ToPtr("kind-community"),
+			URL:    convert.ToPtr("https://www.reddit.com/r/Bitcoin/comments/1"),
+			Status: convert.ToPtr(model.StatusPending),
+			Source: convert.ToPtr("reddit/r/Bitcoin"),
+			Kind:   convert.ToPtr(model.KindCommunity),
+		},
+	}
+
+	require.NoError(t, testTitles.InsertUniqueBatch(ctx, inTitles))
+
+	stored, err := testTitles.ByHashes([]string{"kind-news", "kind-community"}).Select(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	kinds := make(map[string]string, len(stored))
+	for _, title := range stored {
+		kinds[convert.FromPtr(title.Hash)] = convert.FromPtr(title.Kind)
+	}
+	// titles without the kind are news
+	require.Equal(t, model.KindNews, kinds["kind-news"])
+	require.Equal(t, model.KindCommunity, kinds["kind-community"])
+}
+
+func ClaimDisjointBatches(t *testing.T, log *logrus.Entry, db *sqlx.DB) {
+	ctx := context.Background()
+
+	inTitles := make([]model.Title, 6)
+	for i := range inTitles {
+		inTitles[i] = model.Title{
+			Title:  convert.ToPtr(fmt.Sprintf("Claimed title %d", i)),
+			Hash:   convert.ToPtr(fmt.Sprintf("claim-%d", i)),
+			URL:    convert.ToPtr(fmt.Sprintf("https://example.com/claim/%d", i)),
+			Status: convert.ToPtr(model.StatusPending),
+			Source: convert.ToPtr("example"),
+		}
+	}
+	require.NoError(t, New(db, log).InsertUniqueBatch(ctx, inTitles))
+
+	ids := make([]uuid.UUID, len(inTitles))
+	for i, title := range inTitles {
+		ids[i] = title.ID
+	}
+	lease := time.Now().Add(time.Minute)
+
+	// the first replica holds its batch locked until commit, the second one skips it
+	first, err := db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	defer first.Rollback()
+
+	second, err := db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	defer second.Rollback()
+
+	firstBatch, err := New(first, log).ByIDs(ids).ByStatus(model.StatusPending).Claim(ctx, 2, lease)
+	require.NoError(t, err)
+	require.Len(t, firstBatch, 2)
+
+	secondBatch, err := New(second, log).ByIDs(ids).ByStatus(model.StatusPending).Claim(ctx, 2, lease)
+	require.NoError(t, err)
+	require.Len(t, secondBatch, 2)
+
+	// the next batch of the first replica goes after its last claimed title, returned rows are not ordered
+	lastID := firstBatch[0].ID
+	for _, title := range firstBatch[1:] {
+		if bytes.Compare(title.ID[:], lastID[:]) > 0 {
+			lastID = title.ID
+		}
+	}
+	nextBatch, err := New(first, log).ByIDs(ids).ByStatus(model.StatusPending).After(lastID).Claim(ctx, 2, lease)
+	require.NoError(t, err)
+	require.Len(t, nextBatch, 2)
+
+	claimed := make(map[uuid.UUID]bool)
+	for _, batch := range [][]model.Title{firstBatch, secondBatch, nextBatch} {
+		for _, title := range batch {
+			require.False(t, claimed[title.ID], "title %s is claimed twice", title.ID)
+			require.Equal(t, model.StatusProcessing, convert.FromPtr(title.Status))
+			claimed[title.ID] = true
+		}
+	}
+	require.Len(t, claimed, len(inTitles))
+
+	require.NoError(t, first.Commit())
+	require.NoError(t, second.Commit())
+}
+
+func TestTitles(t *testing.T) {
+	suite := drivers.NewSuite(t)
+	suite.AddTests(InsertUniqueBatchKind, ClaimDisjointBatches)
+
+	suite.SetupSuite()
+	defer suite.CleanupSuite()
+
+	suite.TestRunIntegration()
+}
@@ -0,0 +1,44 @@
+package titles_coins
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common/data/drivers/postgres"
+	"common/data/model"
+	"common/data/queriers"
+)
+
+type titlesCoins struct {
+	log *logrus.Entry
+	ext sqlx.ExtContext
+
+	postgres.Inserter[model.TitleCoin]
+}
+
+func New(ext sqlx.ExtContext, log *logrus.Entry) queriers.TitlesCoinsProvider {
+	return &titlesCoins{
+		log: log.WithField("provider", "titles_coins"),
+		ext: ext,
+
+		Inserter: postgres.NewInserter[model.TitleCoin](ext, log),
+	}
+}
+
+func (t titlesCoins) InsertUniqueBatch(ctx context.Context, entities []model.TitleCoin) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	sql := `
+		INSERT INTO titles_coins (title_id, code) VALUES (:title_id, :code) 
+        	ON CONFLICT (title_id, code) DO NOTHING`
+
+	if _, err := sqlx.NamedExecContext(ctx, t.ext, t.ext.Rebind(sql), entities); err != nil {
+		return errors.Wrap(err, "failed to insert entity into table: titles_coins")
+	}
+	return nil
+}
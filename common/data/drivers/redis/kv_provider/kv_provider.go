@@ -79,3 +79,24 @@ func (k kv) Remove(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+func (k kv) Take(ctx context.Context, key string) (bool, error) {
+	removed, err := k.kvStore.WithContext(ctx).Del(key).Result()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to take entity by key: %s in redis", key)
+	}
+	return removed > 0, nil
+}
+
+// Keys keys are scanned in batches, so redis is not blocked the way KEYS does
+func (k kv) Keys(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	iter := k.kvStore.WithContext(ctx).Scan(0, prefix+"*", 100).Iterator()
+	for iter.Next() {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan keys by prefix: %s in redis", prefix)
+	}
+	return keys, nil
+}
@@ -4,6 +4,22 @@ const (
 	StatusPending   = "pending"
 	StatusProcessed = "processed"
 	StatusFailed    = "failed"
+	// StatusProcessing title is claimed by a parser replica until its lease expires
+	StatusProcessing = "processing"
+	// StatusDisallowed url is disallowed for crawling by the site robots.txt
+	StatusDisallowed = "disallowed"
+	// StatusDead title ran out of crawl attempts
+	StatusDead = "dead"
+	// StatusPurged dead title given up by the operator, the row is kept, so its source can't insert it again
+	StatusPurged = "purged"
+	// StatusRejected article language is not accepted from the title source
+	StatusRejected = "rejected"
+)
+
+const (
+	KindNews = "news"
+	// KindCommunity posts of forums and subreddits, their text comes from the listing itself
+	KindCommunity = "community"
 )
 
 const (
@@ -17,4 +33,8 @@ const (
 	WHITELIST                 = "whitelist"
 	TITLES                    = "titles"
 	RAW_NEWS                  = "raw_news"
+	TITLES_COINS              = "titles_coins"
+	STORIES                   = "stories"
+	DIGEST_JOBS               = "digest_jobs"
+	DIGEST_JOB_LOCALES        = "digest_job_locales"
 )
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DigestJob batch of raw news turned into a digest, the pivot digest is kept so that a retry resumes the locales left
+type DigestJob struct {
+	ID        uuid.UUID  `db:"id,omitempty"`
+	CreatedAt time.Time  `db:"created_at,omitempty"`
+	UpdatedAt *time.Time `db:"updated_at"`
+	// IdempotencyKey derived from the raw news of the batch, the same batch always maps to the same job
+	IdempotencyKey string     `db:"idempotency_key"`
+	DigestID       uuid.UUID  `db:"digest_id"`
+	Media          *NewsMedia `db:"media"`
+	Status         *string    `db:"status"`
+}
+
+func (d DigestJob) TableName() string {
+	return DIGEST_JOBS
+}
+
+type UpdateDigestJobParams struct {
+	UpdatedAt *time.Time `db:"updated_at"`
+	Status    *string    `db:"status"`
+}
+
+func (d UpdateDigestJobParams) TableName() string {
+	return DIGEST_JOBS
+}
+
+// DigestJobLocale locale of the job completed, inserted in the same transaction with the localized news
+type DigestJobLocale struct {
+	ID        uuid.UUID `db:"id,omitempty"`
+	CreatedAt time.Time `db:"created_at,omitempty"`
+	JobID     uuid.UUID `db:"job_id"`
+	Locale    string    `db:"locale"`
+	NewsID    uuid.UUID `db:"news_id"`
+}
+
+func (d DigestJobLocale) TableName() string {
+	return DIGEST_JOB_LOCALES
+}
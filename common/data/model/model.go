@@ -10,7 +10,8 @@ import (
 )
 
 type Model interface {
-	News | Coin | Channel | NewsCoin | NewsChannel | PreferencesChannelCoin | UpdateNewsParams | User | Whitelist | Title | UpdateTitleParams | RawNews
+	News | Coin | Channel | NewsCoin | NewsChannel | PreferencesChannelCoin | UpdateNewsParams | User | Whitelist | Title | UpdateTitleParams | RawNews | TitleCoin | Story |
+		DigestJob | UpdateDigestJobParams | DigestJobLocale
 	TableName() string
 }
 
@@ -14,6 +14,12 @@ const (
 	ResourceTypeImage  = "image"
 )
 
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
+
 type News struct {
 	ID          uuid.UUID  `db:"id,omitempty"`
 	CreatedAt   time.Time  `db:"created_at,omitempty"`
@@ -24,6 +30,9 @@ type News struct {
 	// Data
 	Media *NewsMedia `db:"media"`
 
+	// DigestID localized versions of the same digest share it
+	DigestID *uuid.UUID `db:"digest_id"`
+
 	Locale         *string `db:"locale"`
 	Source         *string `db:"source"`
 	OriginalSource *string `db:"original_source"`
@@ -42,6 +51,18 @@ type NewsMedia struct {
 	Title     *string             `json:"title"`
 	Text      *string             `json:"text"`
 	Resources []NewsMediaResource `json:"resources"`
+	// Items structured digest, text is its plain rendering, news generated before have the text only
+	Items []NewsItem `json:"items,omitempty"`
+}
+
+// NewsItem single piece of the digest, sources are ids of the source resources it is based on
+type NewsItem struct {
+	Headline   string   `json:"headline"`
+	Body       string   `json:"body"`
+	Sources    []string `json:"sources"`
+	Coins      []string `json:"coins"`
+	Sentiment  string   `json:"sentiment"`
+	Importance int      `json:"importance"`
 }
 
 type NewsMediaResource struct {
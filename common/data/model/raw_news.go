@@ -1,18 +1,50 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 type RawNews struct {
-	ID        uuid.UUID `db:"id,omitempty"`
-	CreatedAt time.Time `db:"created_at,omitempty"`
-	TitleID   uuid.UUID `db:"title_id"`
-	Body      *string   `db:"body"`
+	ID        uuid.UUID    `db:"id,omitempty"`
+	CreatedAt time.Time    `db:"created_at,omitempty"`
+	TitleID   uuid.UUID    `db:"title_id"`
+	Body      *string      `db:"body"`
+	Meta      *RawNewsMeta `db:"meta"`
+	// Language ISO 639-1 code of the body, LanguageUndetermined if detection was not reliable
+	Language *string `db:"language"`
 }
 
+// LanguageUndetermined BCP 47 code for the undetermined language
+const LanguageUndetermined = "und"
+
 func (t RawNews) TableName() string {
 	return RAW_NEWS
 }
+
+// RawNewsMeta page metadata of the article
+type RawNewsMeta struct {
+	// Images best first
+	Images       []string `json:"images,omitempty"`
+	Author       string   `json:"author,omitempty"`
+	CanonicalURL string   `json:"canonical_url,omitempty"`
+	// PublishedAt publish time from the page, more accurate than the one listed by the source
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+func (m RawNewsMeta) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *RawNewsMeta) Scan(value any) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &m)
+}
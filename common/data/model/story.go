@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Story groups near-duplicate titles, canonical title is the first one seen, others are alternates
+type Story struct {
+	ID               uuid.UUID `db:"id,omitempty"`
+	CreatedAt        time.Time `db:"created_at,omitempty"`
+	CanonicalTitleID uuid.UUID `db:"canonical_title_id"`
+	// Simhash fingerprint of the canonical title and body, stored as signed since postgres has no unsigned bigint
+	Simhash int64 `db:"simhash"`
+}
+
+func (s Story) TableName() string {
+	return STORIES
+}
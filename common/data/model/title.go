@@ -17,6 +17,19 @@ type Title struct {
 	Status      *string    `db:"status"`
 	Source      *string    `db:"source"`
 	ReleaseDate *time.Time `db:"release_date"`
+	// Kind KindNews for editorial articles, KindCommunity for forum posts
+	Kind *string `db:"kind"`
+
+	// Attempts number of failed attempts to crawl the title url
+	Attempts      *int       `db:"attempts"`
+	LastError     *string    `db:"last_error"`
+	NextAttemptAt *time.Time `db:"next_attempt_at"`
+
+	// StoryID group of near-duplicate titles the title belongs to
+	StoryID *uuid.UUID `db:"story_id"`
+
+	// Coins tagged by the source itself, linked to the title via titles_coins
+	Coins []Coin `db:"-"`
 }
 
 func (t Title) TableName() string {
@@ -24,8 +37,13 @@ func (t Title) TableName() string {
 }
 
 type UpdateTitleParams struct {
-	UpdatedAt *time.Time `db:"updated_at"`
-	Status    *string    `db:"status"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+	Status        *string    `db:"status"`
+	Attempts      *int       `db:"attempts"`
+	LastError     *string    `db:"last_error"`
+	NextAttemptAt *time.Time `db:"next_attempt_at"`
+	StoryID       *uuid.UUID `db:"story_id"`
+	ReleaseDate   *time.Time `db:"release_date"`
 }
 
 func (t UpdateTitleParams) TableName() string {
@@ -0,0 +1,13 @@
+package model
+
+import "github.com/google/uuid"
+
+type TitleCoin struct {
+	ID      uuid.UUID `db:"id,omitempty"`
+	TitleID uuid.UUID `db:"title_id"`
+	Code    string    `db:"code"`
+}
+
+func (t TitleCoin) TableName() string {
+	return TITLES_COINS
+}
@@ -40,6 +40,8 @@ type NewsProvider interface {
 
 	BySources(sources ...string) NewsProvider
 	ByIDs(ids []uuid.UUID) NewsProvider
+	// ByDigestIDs localized versions of the digests
+	ByDigestIDs(ids []uuid.UUID) NewsProvider
 
 	// ByCoins TODO: maybe implement results filtering by coins later (but this might slow down,
 	// since then we need to get news for each channel independently
@@ -106,19 +108,62 @@ type TitlesProvider interface {
 	Inserter[model.Title]
 	Selector[model.Title]
 	Updater[model.UpdateTitleParams, model.Title]
+	Remover[model.Title]
 
 	ByIDs(ids []uuid.UUID) TitlesProvider
+	ByHashes(hashes []string) TitlesProvider
+	ByURLs(urls []string) TitlesProvider
 	ByStatus(status ...string) TitlesProvider
+	ByStoryIDs(ids []uuid.UUID) TitlesProvider
+	// DueAt titles that have no scheduled next attempt or whose next attempt is not after t
+	DueAt(t time.Time) TitlesProvider
+	// After keyset pagination, titles with id greater than the given one
+	After(id uuid.UUID) TitlesProvider
+
+	// Claim locks up to limit titles skipping the ones locked by other replicas
+	// and moves them to processing leased until the given time
+	Claim(ctx context.Context, limit uint64, leaseUntil time.Time) ([]model.Title, error)
 
 	InsertUniqueBatch(ctx context.Context, entities []model.Title) error
 }
 
+type StoriesProvider interface {
+	Inserter[model.Story]
+	Selector[model.Story]
+
+	ByIDs(ids []uuid.UUID) StoriesProvider
+	CreatedAfter(t time.Time) StoriesProvider
+}
+
+type DigestJobsProvider interface {
+	Inserter[model.DigestJob]
+	Selector[model.DigestJob]
+	Updater[model.UpdateDigestJobParams, model.DigestJob]
+
+	ByIDs(ids []uuid.UUID) DigestJobsProvider
+	ByIdempotencyKeys(keys ...string) DigestJobsProvider
+}
+
+type DigestJobLocalesProvider interface {
+	Inserter[model.DigestJobLocale]
+	Selector[model.DigestJobLocale]
+
+	ByJobIDs(ids []uuid.UUID) DigestJobLocalesProvider
+}
+
+type TitlesCoinsProvider interface {
+	Inserter[model.TitleCoin]
+
+	InsertUniqueBatch(ctx context.Context, entities []model.TitleCoin) error
+}
+
 type RawNewsProvider interface {
 	Inserter[model.RawNews]
 	Selector[model.RawNews]
 	Remover[model.RawNews]
 
 	ByIDs(ids []uuid.UUID) RawNewsProvider
+	ByTitleIDs(ids []uuid.UUID) RawNewsProvider
 
 	Limit(l uint64) RawNewsProvider
 	Offset(o uint64) RawNewsProvider
@@ -136,4 +181,8 @@ type KVProvider interface {
 	SetStruct(ctx context.Context, key string, value any, exp time.Duration) (string, error)
 
 	Remove(ctx context.Context, key string) error
+	// Take removes the key, only one of the concurrent callers taking the same key gets true
+	Take(ctx context.Context, key string) (bool, error)
+	// Keys keys starting with the prefix
+	Keys(ctx context.Context, prefix string) ([]string, error)
 }
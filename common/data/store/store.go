@@ -12,9 +12,13 @@ import (
 	"common/data/drivers/postgres/raw_news"
 
 	"common/data/drivers/postgres/channels"
+	"common/data/drivers/postgres/digest_job_locales"
+	"common/data/drivers/postgres/digest_jobs"
 	"common/data/drivers/postgres/news_channels"
 	"common/data/drivers/postgres/preferences_channel_coins"
+	"common/data/drivers/postgres/stories"
 	"common/data/drivers/postgres/titles"
+	"common/data/drivers/postgres/titles_coins"
 	"common/data/drivers/postgres/users"
 	"common/data/drivers/postgres/whitelist"
 	"common/data/drivers/redis/kv_provider"
@@ -38,6 +42,10 @@ type DataProvider interface {
 	UsersProvider() queriers.UsersProvider
 	TitlesProvider() queriers.TitlesProvider
 	RawNewsProvider() queriers.RawNewsProvider
+	TitlesCoinsProvider() queriers.TitlesCoinsProvider
+	StoriesProvider() queriers.StoriesProvider
+	DigestJobsProvider() queriers.DigestJobsProvider
+	DigestJobLocalesProvider() queriers.DigestJobLocalesProvider
 
 	InTx(ctx context.Context, fn func(dp DataProvider) error) error
 
@@ -85,7 +93,29 @@ func (d dataProvider) RawNewsProvider() queriers.RawNewsProvider {
 	return raw_news.New(d.ext(), d.log)
 }
 
-func (d dataProvider) InTx(ctx context.Context, fn func(dp DataProvider) error) error {
+func (d dataProvider) TitlesCoinsProvider() queriers.TitlesCoinsProvider {
+	return titles_coins.New(d.ext(), d.log)
+}
+
+func (d dataProvider) StoriesProvider() queriers.StoriesProvider {
+	return stories.New(d.ext(), d.log)
+}
+
+func (d dataProvider) DigestJobsProvider() queriers.DigestJobsProvider {
+	return digest_jobs.New(d.ext(), d.log)
+}
+
+func (d dataProvider) DigestJobLocalesProvider() queriers.DigestJobLocalesProvider {
+	return digest_job_locales.New(d.ext(), d.log)
+}
+
+// InTx runs fn in a transaction, it is rolled back if fn fails or panics,
+// nested calls join the transaction already open
+func (d dataProvider) InTx(ctx context.Context, fn func(dp DataProvider) error) (err error) {
+	if d.inTx {
+		return fn(d)
+	}
+
 	tx, err := d.db.BeginTxx(ctx, &sql.TxOptions{
 		Isolation: 0,
 		ReadOnly:  false,
@@ -94,6 +124,19 @@ func (d dataProvider) InTx(ctx context.Context, fn func(dp DataProvider) error)
 		return errors.Wrap(err, "failed to begin transaction")
 	}
 
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			_ = tx.Rollback()
+			panic(rvr)
+		}
+		if err != nil {
+			// failed commit ends the tx as well
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				d.log.WithError(rbErr).Error("failed to rollback tx")
+			}
+		}
+	}()
+
 	if err = fn(d.new(tx, d.db, d.log.WithField("tx", "[TRANSACTION]"))); err != nil {
 		return errors.Wrap(err, "failed to run transaction")
 	}
@@ -0,0 +1,35 @@
+package simhash
+
+import "testing"
+
+const article = `Bitcoin rallied above $30,000 on Tuesday for the first time since June, as traders
+bet that the approval of a spot exchange-traded fund in the United States is getting closer.
+The largest cryptocurrency by market value gained as much as 5% before paring some of the advance,
+while ether and other major tokens followed it higher.`
+
+func TestFingerprintNearDuplicates(t *testing.T) {
+	syndicated := `BITCOIN rallied above $30,000 on Tuesday for the first time since June, as traders
+bet that approval of a spot exchange-traded fund in the US is getting closer.
+The largest cryptocurrency by market value gained as much as 5% before paring some of the advance,
+while ether and other major tokens followed it higher. (Reporting by a staff writer)`
+
+	if d := Distance(Fingerprint(article), Fingerprint(syndicated)); d > 8 {
+		t.Errorf("expected near duplicates to be close, got distance %d", d)
+	}
+}
+
+func TestFingerprintDifferentStories(t *testing.T) {
+	other := `The Ethereum foundation announced a new grants round focused on zero-knowledge research,
+with funding available to academic teams and independent developers working on proving systems,
+client diversity and tooling for the upcoming protocol upgrades.`
+
+	if d := Distance(Fingerprint(article), Fingerprint(other)); d < 16 {
+		t.Errorf("expected different stories to be far apart, got distance %d", d)
+	}
+}
+
+func TestFingerprintIgnoresCaseAndPunctuation(t *testing.T) {
+	if Fingerprint("Hello, World! Crypto news today.") != Fingerprint("hello world crypto news today") {
+		t.Error("expected normalized texts to have equal fingerprints")
+	}
+}
@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"gpt/internal/config"
+)
+
+const (
+	defaultAnthropicURL   = "https://api.anthropic.com"
+	anthropicVersion      = "2023-06-01"
+	defaultAnthropicModel = "claude-3-haiku-20240307"
+	// defaultAnthropicMaxTokens max_tokens is mandatory for the Messages API
+	defaultAnthropicMaxTokens = 2048
+)
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float32           `json:"temperature,omitempty"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	ToolChoice  *anthropicChoice   `json:"tool_choice,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type anthropicChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Error      *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// anthropicBot talks to Anthropic Messages API, system prompt is a separate field there rather than a message
+type anthropicBot struct {
+	log *logrus.Entry
+
+	provider config.ProviderConfig
+
+	client *http.Client
+}
+
+func NewAnthropic(cfg config.Config, provider config.ProviderConfig) Bot {
+	if provider.Model == "" {
+		provider.Model = defaultAnthropicModel
+	}
+	if provider.BaseURL == "" {
+		provider.BaseURL = defaultAnthropicURL
+	}
+	if provider.MaxTokens <= 0 {
+		provider.MaxTokens = defaultAnthropicMaxTokens
+	}
+
+	return &anthropicBot{
+		log:      cfg.Logging().WithField("[BOT]", provider.Name).WithField("model", provider.Model),
+		provider: provider,
+
+		client: &http.Client{},
+	}
+}
+
+func (b *anthropicBot) Ask(ctx context.Context, prompt, context string, language string) (*Message, error) {
+	respBody, err := b.send(ctx, prompt, context, language, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	for _, block := range respBody.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return nil, errors.Errorf("messages response has no text, stop reason: %s", respBody.StopReason)
+	}
+
+	return &Message{Text: text.String()}, nil
+}
+
+// AskFunction function is passed as the only tool, and the model is forced to use it
+func (b *anthropicBot) AskFunction(ctx context.Context, prompt, context string, language string, fn Function) (*Message, error) {
+	respBody, err := b.send(ctx, prompt, context, language, &fn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range respBody.Content {
+		if block.Type == "tool_use" && block.Name == fn.Name {
+			return &Message{Text: string(block.Input)}, nil
+		}
+	}
+	return nil, errors.Errorf("messages response has no %s tool use, stop reason: %s", fn.Name, respBody.StopReason)
+}
+
+func (b *anthropicBot) send(ctx context.Context, prompt, context string, language string, fn *Function) (*anthropicResponse, error) {
+	system := append([]string{context}, instructions(language)...)
+
+	messagesReq := anthropicRequest{
+		Model:       b.provider.Model,
+		MaxTokens:   b.provider.MaxTokens,
+		Temperature: b.provider.Temperature,
+		System:      strings.TrimSpace(strings.Join(system, "\n")),
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt},
+		},
+	}
+	if fn != nil {
+		messagesReq.Tools = []anthropicTool{
+			{Name: fn.Name, Description: fn.Description, InputSchema: fn.Parameters},
+		}
+		messagesReq.ToolChoice = &anthropicChoice{Type: "tool", Name: fn.Name}
+	}
+
+	reqBody, err := json.Marshal(messagesReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal messages request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(b.provider.BaseURL, "/")+"/v1/messages", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create messages request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", b.provider.AuthToken)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send messages request")
+	}
+	defer resp.Body.Close()
+
+	var respBody anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode messages response, status code: %d", resp.StatusCode)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if respBody.Error != nil {
+			return nil, errors.Errorf("messages request failed: %d %s: %s", resp.StatusCode, respBody.Error.Type, respBody.Error.Message)
+		}
+		return nil, errors.Errorf("messages request failed: %d", resp.StatusCode)
+	}
+
+	if respBody.StopReason == "max_tokens" {
+		b.log.WithField("max-tokens", b.provider.MaxTokens).Warn("reply was cut by max tokens...")
+	}
+
+	return &respBody, nil
+}
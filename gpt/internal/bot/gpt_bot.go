@@ -1,11 +1,46 @@
 package bot
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strings"
+)
 
 type Message struct {
 	Text string
 }
 
+// Function structured reply, the model is forced to call the function and the call arguments are the reply
+type Function struct {
+	Name        string
+	Description string
+	// Parameters JSON schema of the arguments
+	Parameters any
+}
+
 type Bot interface {
 	Ask(ctx context.Context, prompt, context string, language string) (*Message, error)
+	// AskFunction replies with JSON arguments of the function, they are not validated against the schema
+	AskFunction(ctx context.Context, prompt, context string, language string, fn Function) (*Message, error)
+}
+
+// instructions follow the prompt in every request, they pin the language of the reply
+func instructions(language string) []string {
+	return []string{
+		"Follow these four instructions below in all your responses:",
+		fmt.Sprintf("Your entire reply should be translated to the following language: %s", language),
+		fmt.Sprintf("Use %s language only;", language),
+		fmt.Sprintf("Use %s alphabet whenever possible;", language),
+		fmt.Sprintf("Translate any other language to the %s language whenever possible.", language),
+	}
+}
+
+// fromCodeBlock servers without function calling reply with JSON in the message, often wrapped into a code block
+func fromCodeBlock(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "```"), "json")
+	return strings.TrimSpace(strings.TrimSuffix(text, "```"))
 }
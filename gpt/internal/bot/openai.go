@@ -1,74 +1,171 @@
 package bot
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"encoding/json"
+	"io"
+	"net/http"
 
 	"github.com/pkg/errors"
 	"github.com/sashabaranov/go-openai"
 	"github.com/sirupsen/logrus"
 
+	"common/convert"
 	"gpt/internal/config"
 )
 
+// openAIBot talks to OpenAI chat completions API, Azure OpenAI and OpenAI-compatible servers speak the same API
 type openAIBot struct {
 	log *logrus.Entry
 
-	cfg config.Config
+	provider config.ProviderConfig
 
 	client *openai.Client
 }
 
-func NewOpenAI(cfg config.Config) Bot {
+func NewOpenAI(cfg config.Config, provider config.ProviderConfig) Bot {
+	if provider.Model == "" {
+		provider.Model = openai.GPT3Dot5Turbo16K
+	}
+
 	return &openAIBot{
-		log: cfg.Logging().WithField("[BOT]", openai.GPT3Dot5Turbo16K),
-		cfg: cfg,
+		log:      cfg.Logging().WithField("[BOT]", provider.Name).WithField("model", provider.Model),
+		provider: provider,
+
+		client: openai.NewClientWithConfig(clientConfig(provider)),
+	}
+}
 
-		client: openai.NewClientWithConfig(openai.DefaultConfig(cfg.AuthToken())),
+func clientConfig(provider config.ProviderConfig) openai.ClientConfig {
+	clientCfg := apiConfig(provider)
+	if t := provider.Temperature; t != nil && *t == 0 {
+		clientCfg.HTTPClient = &http.Client{Transport: zeroTemperature{next: http.DefaultTransport}}
 	}
+	return clientCfg
+}
+
+// apiConfig endpoint and auth of OpenAI or Azure
+func apiConfig(provider config.ProviderConfig) openai.ClientConfig {
+	if provider.Type == config.ProviderAzure {
+		clientCfg := openai.DefaultAzureConfig(provider.AuthToken, provider.BaseURL)
+		if provider.APIVersion != "" {
+			clientCfg.APIVersion = provider.APIVersion
+		}
+		if provider.Deployment != "" {
+			clientCfg.AzureModelMapperFunc = func(string) string {
+				return provider.Deployment
+			}
+		}
+		return clientCfg
+	}
+
+	clientCfg := openai.DefaultConfig(provider.AuthToken)
+	if provider.BaseURL != "" {
+		clientCfg.BaseURL = provider.BaseURL
+	}
+	return clientCfg
+}
+
+// zeroTemperature the client omits zero temperature, which the API takes for its default one,
+// so the explicit 0 is put back into the request body
+type zeroTemperature struct {
+	next http.RoundTripper
+}
+
+func (z zeroTemperature) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return z.next.RoundTrip(req)
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body")
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &body); err == nil {
+		if _, ok := body["temperature"]; !ok {
+			body["temperature"] = json.RawMessage("0")
+			if raw, err = json.Marshal(body); err != nil {
+				return nil, errors.Wrap(err, "failed to marshal request body")
+			}
+		}
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(raw))
+	req.ContentLength = int64(len(raw))
+	return z.next.RoundTrip(req)
 }
 
 func (b *openAIBot) Ask(ctx context.Context, prompt, context string, language string) (*Message, error) {
-	resp, err := b.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo16K,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: context,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "Follow these four instructions below in all your responses:",
-				},
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: fmt.Sprintf("Your entire reply should be translated to the following language: %s", language),
-				},
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: fmt.Sprintf("Use %s language only;", language),
-				},
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: fmt.Sprintf("Use %s alphabet whenever possible;", language),
-				},
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: fmt.Sprintf("Translate any other language to the %s language whenever possible.", language),
-				},
-			},
+	msg, err := b.complete(ctx, prompt, context, language, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{Text: msg.Content}, nil
+}
+
+func (b *openAIBot) AskFunction(ctx context.Context, prompt, context string, language string, fn Function) (*Message, error) {
+	msg, err := b.complete(ctx, prompt, context, language, &fn)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.FunctionCall == nil {
+		// compatible servers without function calling reply with the JSON in the message
+		b.log.WithField("function", fn.Name).Debug("reply has no function call, taking the message...")
+		return &Message{Text: fromCodeBlock(msg.Content)}, nil
+	}
+	return &Message{Text: msg.FunctionCall.Arguments}, nil
+}
+
+func (b *openAIBot) complete(ctx context.Context, prompt, context string, language string, fn *Function) (*openai.ChatCompletionMessage, error) {
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: context,
+		},
+		{
+			Role:    openai.ChatMessageRoleUser,
+			Content: prompt,
 		},
-	)
+	}
+	for _, instruction := range instructions(language) {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: instruction,
+		})
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model:       b.provider.Model,
+		Messages:    messages,
+		Temperature: convert.FromPtr(b.provider.Temperature),
+		MaxTokens:   b.provider.MaxTokens,
+	}
+	if fn != nil {
+		req.Functions = []openai.FunctionDefinition{
+			{
+				Name:        fn.Name,
+				Description: fn.Description,
+				Parameters:  fn.Parameters,
+			},
+		}
+		req.FunctionCall = openai.FunctionCall{Name: fn.Name}
+	}
 
+	resp, err := b.client.CreateChatCompletion(ctx, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create chat completion request")
 	}
 
-	return &Message{Text: resp.Choices[0].Message.Content}, nil
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("chat completion has no choices")
+	}
+
+	return &resp.Choices[0].Message, nil
 }
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"common/convert"
+	"gpt/internal/config"
+)
+
+func TestTemperatureSent(t *testing.T) {
+	var temperature any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		temperature = body["temperature"]
+
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "ok"}}},
+		})
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		temperature *float32
+		want        any
+	}{
+		// the explicit 0 must not fall back to the API default
+		{convert.ToPtr[float32](0), 0.0},
+		{convert.ToPtr[float32](0.5), 0.5},
+		{nil, nil},
+	}
+
+	for _, c := range cases {
+		provider := config.ProviderConfig{
+			Type:        config.ProviderOpenAICompatible,
+			BaseURL:     server.URL,
+			Model:       "test",
+			Temperature: c.temperature,
+		}
+		b := &openAIBot{
+			log:      logrus.NewEntry(logrus.New()),
+			provider: provider,
+			client:   openai.NewClientWithConfig(clientConfig(provider)),
+		}
+
+		temperature = nil
+		msg, err := b.Ask(context.Background(), "prompt", "context", "en")
+		require.NoError(t, err)
+		require.Equal(t, "ok", msg.Text)
+		require.Equal(t, c.want, temperature, "temperature: %v", c.temperature)
+	}
+}
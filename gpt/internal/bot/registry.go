@@ -0,0 +1,27 @@
+package bot
+
+import (
+	"github.com/pkg/errors"
+
+	"gpt/internal/config"
+)
+
+type constructor func(cfg config.Config, provider config.ProviderConfig) Bot
+
+var constructors = map[string]constructor{
+	config.ProviderOpenAI:           NewOpenAI,
+	config.ProviderOpenAICompatible: NewOpenAI,
+	config.ProviderAzure:            NewOpenAI,
+	config.ProviderAnthropic:        NewAnthropic,
+}
+
+// New builds the bot of the provider selected in the gpt config
+func New(cfg config.Config) Bot {
+	provider := cfg.Provider()
+
+	newBot, ok := constructors[provider.Type]
+	if !ok {
+		panic(errors.Errorf("unknown gpt provider type: %s, for provider: %s", provider.Type, provider.Name))
+	}
+	return newBot(cfg, provider)
+}
@@ -1,19 +1,74 @@
 package config
 
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	ProviderOpenAI = "openai"
+	// ProviderOpenAICompatible local servers speaking the OpenAI chat API: Ollama, llama.cpp, vLLM
+	ProviderOpenAICompatible = "openai_compatible"
+	ProviderAzure            = "azure"
+	ProviderAnthropic        = "anthropic"
+)
+
+// defaultProvider name of the provider built from the legacy gpt.auth_token when no providers are configured
+const defaultProvider = ProviderOpenAI
+
 type BotConfig interface {
-	AuthToken() string
+	// Provider LLM provider selected by gpt.provider
+	Provider() ProviderConfig
+}
+
+// ProviderConfig LLM backend, type picks the API flavour, so several providers of the same type can be configured
+type ProviderConfig struct {
+	Name      string `yaml:"-"`
+	Type      string `yaml:"type"`
+	AuthToken string `yaml:"auth_token"`
+	Model     string `yaml:"model"`
+	BaseURL   string `yaml:"base_url"`
+	// Temperature provider default if not set, explicit 0 is sent as well
+	Temperature *float32 `yaml:"temperature"`
+	MaxTokens   int      `yaml:"max_tokens"`
+	// APIVersion and Deployment are azure only, deployment defaults to the model name
+	APIVersion string `yaml:"api_version"`
+	Deployment string `yaml:"deployment"`
+}
+
+type yamlBotConfig struct {
+	AuthToken string                    `yaml:"auth_token"`
+	Provider  string                    `yaml:"provider"`
+	Providers map[string]ProviderConfig `yaml:"providers"`
 }
 
 type botConfig struct {
-	authToken string
+	provider ProviderConfig
 }
 
-func NewBotConfig(authToken string) BotConfig {
+func NewBotConfig(cfg yamlBotConfig) BotConfig {
+	name := cfg.Provider
+	if name == "" {
+		name = defaultProvider
+	}
+
+	provider, ok := cfg.Providers[name]
+	switch {
+	case !ok && len(cfg.Providers) == 0:
+		// configs written before the providers were introduced only have the OpenAI token
+		provider = ProviderConfig{Type: ProviderOpenAI, AuthToken: cfg.AuthToken}
+	case !ok:
+		panic(errors.Errorf("unknown gpt provider: %s", name))
+	}
+
+	provider.Name = name
+	if provider.Type == "" {
+		provider.Type = name
+	}
 	return &botConfig{
-		authToken: authToken,
+		provider: provider,
 	}
 }
 
-func (b botConfig) AuthToken() string {
-	return b.authToken
+func (b botConfig) Provider() ProviderConfig {
+	return b.provider
 }
@@ -14,12 +14,16 @@ type Config interface {
 	commoncfg.Config
 	Generator
 	BotConfig
+	Summarizer
+	Translation
 }
 
 type config struct {
 	commoncfg.Config
 	Generator
 	BotConfig
+	Summarizer
+	Translation
 }
 
 type yamlConfig struct {
@@ -28,11 +32,14 @@ type yamlConfig struct {
 	KVStore   commoncfg.YamlKVStoreConfig  `yaml:"kv_store"`
 	Runtime   commoncfg.YamlRuntimeConfig  `yaml:"runtime"`
 	GPTConfig struct {
-		AuthToken     string        `yaml:"auth_token"`
-		GenerateEvery time.Duration `yaml:"generate_every"`
-		QueryContext  string        `yaml:"query_context"`
-		Prompt        string        `yaml:"prompt"`
-		ImagesPrompt  string        `yaml:"images_prompt"`
+		yamlBotConfig `yaml:",inline"`
+		GenerateEvery time.Duration        `yaml:"generate_every"`
+		QueryContext  string               `yaml:"query_context"`
+		Prompt        string               `yaml:"prompt"`
+		ImagesPrompt  string               `yaml:"images_prompt"`
+		Summarizer    yamlSummarizerConfig `yaml:"summarizer"`
+		PivotLocale   string               `yaml:"pivot_locale"`
+		Translator    TranslatorConfig     `yaml:"translator"`
 	} `yaml:"gpt"`
 }
 
@@ -50,8 +57,10 @@ func NewFromFile(path string) Config {
 	}
 
 	return &config{
-		Config:    commoncfg.New(cfg.LogLevel, cfg.Runtime, cfg.Database, cfg.KVStore),
-		BotConfig: NewBotConfig(cfg.GPTConfig.AuthToken),
-		Generator: NewGenerator(cfg.GPTConfig.GenerateEvery, cfg.GPTConfig.ImagesPrompt, cfg.GPTConfig.QueryContext),
+		Config:      commoncfg.New(cfg.LogLevel, cfg.Runtime, cfg.Database, cfg.KVStore),
+		BotConfig:   NewBotConfig(cfg.GPTConfig.yamlBotConfig),
+		Generator:   NewGenerator(cfg.GPTConfig.GenerateEvery, cfg.GPTConfig.ImagesPrompt, cfg.GPTConfig.QueryContext),
+		Summarizer:  NewSummarizer(cfg.GPTConfig.Summarizer),
+		Translation: NewTranslation(cfg.GPTConfig.PivotLocale, cfg.GPTConfig.Translator),
 	}
 }
@@ -0,0 +1,50 @@
+package config
+
+const (
+	// defaultMaxInputTokens leaves room for the reply within 16k context of gpt-3.5-turbo-16k
+	defaultMaxInputTokens = 12000
+	defaultChunkTokens    = 3000
+)
+
+type Summarizer interface {
+	// MaxInputTokens budget of the digest prompt, story summaries are condensed until they fit
+	MaxInputTokens() int
+	// ChunkTokens longer articles are split into chunks summarized one by one
+	ChunkTokens() int
+}
+
+type yamlSummarizerConfig struct {
+	MaxInputTokens int `yaml:"max_input_tokens"`
+	ChunkTokens    int `yaml:"chunk_tokens"`
+}
+
+type summarizer struct {
+	maxInputTokens int
+	chunkTokens    int
+}
+
+func NewSummarizer(cfg yamlSummarizerConfig) Summarizer {
+	if cfg.MaxInputTokens <= 0 {
+		cfg.MaxInputTokens = defaultMaxInputTokens
+	}
+	if cfg.ChunkTokens <= 0 {
+		cfg.ChunkTokens = defaultChunkTokens
+	}
+	// a chunk has to fit into the prompt together with the instructions
+	if cfg.ChunkTokens > cfg.MaxInputTokens/2 {
+		cfg.ChunkTokens = cfg.MaxInputTokens / 2
+	}
+
+	return &summarizer{
+		maxInputTokens: cfg.MaxInputTokens,
+		chunkTokens:    cfg.ChunkTokens,
+	}
+}
+
+func (s summarizer) MaxInputTokens() int {
+	return s.maxInputTokens
+}
+
+func (s summarizer) ChunkTokens() int {
+	return s.chunkTokens
+}
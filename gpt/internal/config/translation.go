@@ -0,0 +1,51 @@
+package config
+
+import "strings"
+
+const (
+	// TranslatorLLM digest is translated by the configured gpt provider
+	TranslatorLLM   = "llm"
+	TranslatorDeepL = "deepl"
+
+	defaultPivotLocale = "en"
+)
+
+type Translation interface {
+	// PivotLocale the digest is generated once in this locale, then translated to the rest
+	PivotLocale() string
+	Translator() TranslatorConfig
+}
+
+// TranslatorConfig backend translating the digest, auth token and base url are only needed by the external ones
+type TranslatorConfig struct {
+	Type      string `yaml:"type"`
+	AuthToken string `yaml:"auth_token"`
+	BaseURL   string `yaml:"base_url"`
+}
+
+type translation struct {
+	pivotLocale string
+	translator  TranslatorConfig
+}
+
+func NewTranslation(pivotLocale string, translator TranslatorConfig) Translation {
+	if pivotLocale == "" {
+		pivotLocale = defaultPivotLocale
+	}
+	if translator.Type == "" {
+		translator.Type = TranslatorLLM
+	}
+
+	return &translation{
+		pivotLocale: strings.ToLower(pivotLocale),
+		translator:  translator,
+	}
+}
+
+func (t translation) PivotLocale() string {
+	return t.pivotLocale
+}
+
+func (t translation) Translator() TranslatorConfig {
+	return t.translator
+}
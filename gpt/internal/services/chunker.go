@@ -0,0 +1,113 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// charsPerToken rough ratio of BPE tokenizers for latin and cyrillic texts,
+// ideographs are a token each, so the estimate stays on the safe side for CJK articles
+const charsPerToken = 4
+
+var sentenceEnd = regexp.MustCompile(`([.!?…。！？]["'»”)]*)\s+`)
+
+// estimateTokens approximates the token count without the model tokenizer, which differs between providers
+func estimateTokens(text string) int {
+	chars, ideographs := 0, 0
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			ideographs++
+			continue
+		}
+		chars++
+	}
+	return ideographs + (chars+charsPerToken-1)/charsPerToken
+}
+
+// chunkText splits the text into chunks of up to maxTokens, paragraphs are kept whole whenever possible,
+// longer paragraphs are split by sentences and run-on sentences by words
+func chunkText(text string, maxTokens int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if estimateTokens(text) <= maxTokens {
+		return []string{text}
+	}
+
+	var pieces []piece
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			pieces = append(pieces, splitPiece(p, maxTokens)...)
+		}
+	}
+
+	var (
+		chunks []string
+		buf    strings.Builder
+		tokens int
+	)
+	for _, p := range pieces {
+		if tokens > 0 && tokens+estimateTokens(p.sep)+p.tokens > maxTokens {
+			chunks = append(chunks, buf.String())
+			buf.Reset()
+			tokens = 0
+		}
+		if tokens > 0 {
+			buf.WriteString(p.sep)
+			tokens += estimateTokens(p.sep)
+		}
+		buf.WriteString(p.text)
+		tokens += p.tokens
+	}
+	if tokens > 0 {
+		chunks = append(chunks, buf.String())
+	}
+	return chunks
+}
+
+// piece part of the text packed into chunks as a whole, sep joins it to the previous piece
+type piece struct {
+	text   string
+	sep    string
+	tokens int
+}
+
+func splitPiece(paragraph string, maxTokens int) []piece {
+	if tokens := estimateTokens(paragraph); tokens <= maxTokens {
+		return []piece{{text: paragraph, sep: "\n\n", tokens: tokens}}
+	}
+
+	pieces := make([]piece, 0)
+	for i, sentence := range splitSentences(paragraph) {
+		sep := " "
+		if i == 0 {
+			sep = "\n\n"
+		}
+		if tokens := estimateTokens(sentence); tokens <= maxTokens {
+			pieces = append(pieces, piece{text: sentence, sep: sep, tokens: tokens})
+			continue
+		}
+		for j, word := range strings.Fields(sentence) {
+			if j > 0 {
+				sep = " "
+			}
+			pieces = append(pieces, piece{text: word, sep: sep, tokens: estimateTokens(word)})
+		}
+	}
+	return pieces
+}
+
+func splitSentences(paragraph string) []string {
+	sentences := make([]string, 0)
+	last := 0
+	for _, loc := range sentenceEnd.FindAllStringSubmatchIndex(paragraph, -1) {
+		sentences = append(sentences, paragraph[last:loc[3]])
+		last = loc[1]
+	}
+	if last < len(paragraph) {
+		sentences = append(sentences, paragraph[last:])
+	}
+	return sentences
+}
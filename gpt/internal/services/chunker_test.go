@@ -0,0 +1,39 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	require.Equal(t, 2, estimateTokens("Bitcoin"), "unexpected latin estimate")
+	require.Equal(t, 5, estimateTokens("比特币价格"), "unexpected ideographs estimate")
+}
+
+func TestChunkText(t *testing.T) {
+	paragraphs := []string{
+		strings.Repeat("a", 36),
+		strings.Repeat("b", 36),
+		// longer than a chunk, split by sentences
+		strings.Repeat("c", 30) + ". " + strings.Repeat("d", 30) + "! " + strings.Repeat("e", 30) + ".",
+	}
+
+	chunks := chunkText(strings.Join(paragraphs, "\n\n"), 20)
+
+	want := []string{
+		paragraphs[0] + "\n\n" + paragraphs[1],
+		strings.Repeat("c", 30) + ". " + strings.Repeat("d", 30) + "!",
+		strings.Repeat("e", 30) + ".",
+	}
+	require.Equal(t, want, chunks)
+	for i := range chunks {
+		require.LessOrEqual(t, estimateTokens(chunks[i]), 20, "chunk %d is over the limit", i)
+	}
+
+	// nothing is lost, whatever the size
+	long := strings.Repeat("word ", 1000)
+	got := strings.Join(chunkText(long, 50), " ")
+	require.Equal(t, strings.TrimSpace(long), strings.Join(strings.Fields(got), " "), "run-on text was not chunked whole")
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sashabaranov/go-openai/jsonschema"
+
+	"common/data/model"
+	"common/iteration"
+	"gpt/internal/bot"
+)
+
+const (
+	minImportance = 1
+	maxImportance = 5
+)
+
+var coinCodeRegex = regexp.MustCompile(`^[A-Z0-9]{2,10}$`)
+
+// digestFunction the digest is requested as the arguments of the function call, so it can be validated
+// rather than scraped from the free text
+var digestFunction = bot.Function{
+	Name:        "publish_digest",
+	Description: "Publish the digest of the most important news of the stories given",
+	Parameters: jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"items": {
+				Type:        jsonschema.Array,
+				Description: "Digest items, one per story or per several stories about the same event",
+				Items: &jsonschema.Definition{
+					Type: jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{
+						"headline": {Type: jsonschema.String, Description: "Short headline of the item"},
+						"body":     {Type: jsonschema.String, Description: "A few sentences telling the news"},
+						"sources": {
+							Type:        jsonschema.Array,
+							Description: "Ids of the sources the item is based on, as listed in the story headings",
+							Items:       &jsonschema.Definition{Type: jsonschema.Integer},
+						},
+						"coins": {
+							Type:        jsonschema.Array,
+							Description: "Tickers of the cryptocurrencies the item is about, e.g. BTC, ETH",
+							Items:       &jsonschema.Definition{Type: jsonschema.String},
+						},
+						"sentiment": {
+							Type: jsonschema.String,
+							Enum: []string{model.SentimentPositive, model.SentimentNeutral, model.SentimentNegative},
+						},
+						"importance": {
+							Type:        jsonschema.Integer,
+							Description: "Importance of the news for the readers, from 1 to 5",
+						},
+					},
+					Required: []string{"headline", "body", "sources", "coins", "sentiment", "importance"},
+				},
+			},
+		},
+		Required: []string{"items"},
+	},
+}
+
+type digestReply struct {
+	Items []digestItem `json:"items"`
+}
+
+type digestItem struct {
+	Headline   string   `json:"headline"`
+	Body       string   `json:"body"`
+	Sources    []int    `json:"sources"`
+	Coins      []string `json:"coins"`
+	Sentiment  string   `json:"sentiment"`
+	Importance int      `json:"importance"`
+}
+
+// parseDigest validates the reply, items citing none of the known sources or out of the schema are dropped,
+// the rest go from the most important
+func parseDigest(reply string, sourceIDs map[int]bool) ([]model.NewsItem, []error, error) {
+	var digest digestReply
+	if err := json.Unmarshal([]byte(reply), &digest); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal digest reply")
+	}
+
+	items := make([]model.NewsItem, 0, len(digest.Items))
+	invalid := make([]error, 0)
+	for i, raw := range digest.Items {
+		item, err := raw.validate(sourceIDs)
+		if err != nil {
+			invalid = append(invalid, errors.Wrapf(err, "item %d", i+1))
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if len(items) == 0 {
+		return nil, invalid, ErrEmptyDigest
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Importance > items[j].Importance
+	})
+	return items, invalid, nil
+}
+
+func (d digestItem) validate(sourceIDs map[int]bool) (model.NewsItem, error) {
+	item := model.NewsItem{
+		Headline:   strings.TrimSpace(d.Headline),
+		Body:       strings.TrimSpace(d.Body),
+		Sentiment:  strings.ToLower(strings.TrimSpace(d.Sentiment)),
+		Importance: d.Importance,
+	}
+	if item.Headline == "" || item.Body == "" {
+		return item, errors.New("headline or body is empty")
+	}
+
+	switch item.Sentiment {
+	case model.SentimentPositive, model.SentimentNeutral, model.SentimentNegative:
+	default:
+		return item, errors.Errorf("unknown sentiment: %s", d.Sentiment)
+	}
+
+	if item.Importance < minImportance || item.Importance > maxImportance {
+		return item, errors.Errorf("importance is out of range: %d", d.Importance)
+	}
+
+	// the sources were read, anything else the model cites is made up
+	cited := iteration.Unique(d.Sources)
+	sort.Ints(cited)
+	for _, id := range cited {
+		if sourceIDs[id] {
+			item.Sources = append(item.Sources, strconv.Itoa(id))
+		}
+	}
+	if len(item.Sources) == 0 {
+		return item, errors.Errorf("none of the sources is known: %v", d.Sources)
+	}
+
+	item.Coins = make([]string, 0, len(d.Coins))
+	for _, c := range d.Coins {
+		if code := strings.ToUpper(strings.TrimSpace(c)); coinCodeRegex.MatchString(code) {
+			item.Coins = append(item.Coins, code)
+		}
+	}
+	item.Coins = iteration.Unique(item.Coins)
+	sort.Strings(item.Coins)
+
+	return item, nil
+}
+
+func digestCoins(items []model.NewsItem) []model.Coin {
+	codes := make([]string, 0)
+	for _, item := range items {
+		codes = append(codes, item.Coins...)
+	}
+
+	return iteration.Map(iteration.Unique(codes), func(code string) model.Coin {
+		return model.Coin{
+			Code: code,
+			Slug: code,
+		}
+	})
+}
+
+// digestText plain text of the digest, for the clients not rendering the items
+func digestText(items []model.NewsItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = item.Headline + "\n" + item.Body
+	}
+	return strings.Join(parts, "\n\n")
+}
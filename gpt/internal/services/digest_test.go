@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+)
+
+const digestReplyJSON = `{"items": [
+  {"headline": "ETF delayed", "body": "The SEC postponed the decision.", "sources": [2, 1, 2, 9],
+   "coins": ["btc", " BTC", "B0B", "not a coin"], "sentiment": "Negative", "importance": 3},
+  {"headline": "Made up", "body": "Cites an unknown source.", "sources": [7],
+   "coins": [], "sentiment": "neutral", "importance": 5},
+  {"headline": "Rally", "body": "Ether rallied.", "sources": [3],
+   "coins": ["ETH"], "sentiment": "euphoric", "importance": 4},
+  {"headline": "Upgrade", "body": "The upgrade went live.", "sources": [3],
+   "coins": ["ETH"], "sentiment": "positive", "importance": 5}
+]}`
+
+func TestParseDigest(t *testing.T) {
+	items, invalid, err := parseDigest(digestReplyJSON, map[int]bool{1: true, 2: true, 3: true})
+	require.NoError(t, err)
+
+	want := []model.NewsItem{
+		{Headline: "Upgrade", Body: "The upgrade went live.", Sources: []string{"3"}, Coins: []string{"ETH"}, Sentiment: model.SentimentPositive, Importance: 5},
+		// unknown source and invalid coin codes are dropped, codes with zeros are kept
+		{Headline: "ETF delayed", Body: "The SEC postponed the decision.", Sources: []string{"1", "2"}, Coins: []string{"B0B", "BTC"}, Sentiment: model.SentimentNegative, Importance: 3},
+	}
+	require.Equal(t, want, items)
+	require.Len(t, invalid, 2)
+	require.Len(t, digestCoins(items), 3)
+}
+
+func TestParseDigestEmpty(t *testing.T) {
+	_, _, err := parseDigest(`{"items": []}`, map[int]bool{1: true})
+	require.ErrorIs(t, err, ErrEmptyDigest)
+
+	_, _, err = parseDigest(`Here is your digest`, map[int]bool{1: true})
+	require.Error(t, err, "free text reply is accepted")
+}
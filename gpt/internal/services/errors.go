@@ -2,4 +2,7 @@ package services
 
 import "github.com/pkg/errors"
 
-var ErrShortSummary = errors.New("Failed to generate short summary")
+var (
+	ErrShortSummary = errors.New("Failed to generate short summary")
+	ErrEmptyDigest  = errors.New("Digest has no valid items")
+)
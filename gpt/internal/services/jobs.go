@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common"
+	"common/convert"
+	"common/data"
+	"common/data/model"
+	"common/data/store"
+	"common/iteration"
+	"gpt/internal/bot"
+	"gpt/internal/translator"
+)
+
+// processBatch turns the batch of raw news into the digest job and completes the locales left,
+// every locale is written in its own transaction along with its completion, so a retry resumes rather than duplicates
+func (s service) processBatch(ctx context.Context, b bot.Bot, t translator.Translator, rawNews []model.RawNews) error {
+	job, err := s.digestJob(ctx, b, rawNews)
+	if err != nil {
+		return errors.Wrap(err, "failed to get digest job")
+	}
+
+	if job != nil {
+		if err := s.completeLocales(ctx, t, job); err != nil {
+			return err
+		}
+	}
+
+	rawNewsIDs := iteration.Map(rawNews, func(t model.RawNews) uuid.UUID {
+		return t.ID
+	})
+
+	// the job is done along with the raw news removal, so a finished batch is never picked up again
+	return s.dataProvider.InTx(ctx, func(dp store.DataProvider) error {
+		if err := dp.RawNewsProvider().ByIDs(rawNewsIDs).Remove(ctx, model.RawNews{}); err != nil {
+			return errors.Wrap(err, "failed to remove processed raw news")
+		}
+		if job == nil {
+			return nil
+		}
+
+		if _, err := dp.DigestJobsProvider().ByIDs([]uuid.UUID{job.ID}).Update(ctx, model.UpdateDigestJobParams{
+			Status: convert.ToPtr(model.StatusProcessed),
+		}); err != nil {
+			return errors.Wrap(err, "failed to update digest job")
+		}
+		return nil
+	})
+}
+
+// digestJob job of the batch, the pivot digest is only generated if the batch has no job yet,
+// nil job means the batch has nothing to digest
+func (s service) digestJob(ctx context.Context, b bot.Bot, rawNews []model.RawNews) (*model.DigestJob, error) {
+	key := digestJobKey(rawNews)
+	log := s.log.WithField("idempotency-key", key)
+
+	jobs, err := s.dataProvider.DigestJobsProvider().ByIdempotencyKeys(key).Select(ctx)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		return nil, errors.Wrap(err, "failed to select digest job")
+	}
+	if len(jobs) > 0 {
+		log.WithField("digest-id", jobs[0].DigestID).Info("Resuming digest job...")
+		return &jobs[0], nil
+	}
+
+	// near-duplicates are grouped into stories by the parser, so the digest is summarized per story
+	stories, err := s.collectStories(ctx, rawNews)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect stories")
+	}
+	if len(stories) == 0 {
+		log.Warn("no stories in the batch, skipping...")
+		return nil, nil
+	}
+
+	// every story is read and summarized on its own, then the summaries are reduced into the digest
+	stories, err = s.summarizeStories(ctx, b, stories)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to summarize stories")
+	}
+
+	aggregatedText, err := s.reduceStories(ctx, b, stories)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reduce story summaries")
+	}
+
+	// the sources of the stories are attached to the digest
+	titles := storySources(stories)
+	images := storiesImages(stories, maxDigestImages)
+
+	// the digest is generated once in the pivot locale, so that all the locales tell the same news
+	items, err := s.generateDigest(ctx, b, s.cfg.QueryContext(), aggregatedText, len(titles))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate for pivot locale: %s", s.cfg.PivotLocale())
+	}
+
+	media, err := digestMedia(items, titles, images, common.CurrentTimestamp())
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.dataProvider.DigestJobsProvider().Insert(ctx, model.DigestJob{
+		IdempotencyKey: key,
+		// localized news of the digest are linked by the digest id
+		DigestID: uuid.New(),
+		Media:    media,
+		Status:   convert.ToPtr(model.StatusPending),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert digest job")
+	}
+
+	log.WithField("digest-id", job.DigestID).Debug("Created digest job")
+	return job, nil
+}
+
+func (s service) completeLocales(ctx context.Context, t translator.Translator, job *model.DigestJob) error {
+	completed, err := s.dataProvider.DigestJobLocalesProvider().ByJobIDs([]uuid.UUID{job.ID}).Select(ctx)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		return errors.Wrap(err, "failed to select completed locales")
+	}
+
+	done := make(map[string]bool, len(completed))
+	for _, c := range completed {
+		done[c.Locale] = true
+	}
+
+	for _, locale := range s.cfg.Locales() {
+		log := s.log.WithFields(logrus.Fields{
+			"locale":    locale,
+			"digest-id": job.DigestID,
+		})
+		if done[locale] {
+			log.Debug("Locale is already completed, skipping...")
+			continue
+		}
+
+		log.Debug("Translating for locale")
+		localized, err := s.translateDigest(ctx, t, job.Media.Items, locale)
+		if err != nil {
+			return errors.Wrapf(err, "failed to translate for locale: %s", locale)
+		}
+
+		news := newsForLocale(job, locale, localized)
+		if err := s.dataProvider.InTx(ctx, func(dp store.DataProvider) error {
+			createdNews, err := addNews(ctx, dp, news, digestCoins(localized))
+			if err != nil {
+				return errors.Wrap(err, "failed to add news")
+			}
+
+			_, err = dp.DigestJobLocalesProvider().Insert(ctx, model.DigestJobLocale{
+				JobID:  job.ID,
+				Locale: locale,
+				NewsID: createdNews.ID,
+			})
+			return errors.Wrap(err, "failed to insert completed locale")
+		}); err != nil {
+			return errors.Wrapf(err, "failed to complete locale: %s", locale)
+		}
+	}
+
+	s.log.WithField("digest-id", job.DigestID).Debug("Finished generating")
+	return nil
+}
+
+// digestJobKey the same raw news always make the same key, no matter the order they are selected in
+func digestJobKey(rawNews []model.RawNews) string {
+	ids := iteration.Map(rawNews, func(r model.RawNews) string {
+		return r.ID.String()
+	})
+	sort.Strings(ids)
+
+	h := sha256.New()
+	for _, id := range ids {
+		h.Write([]byte(id))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
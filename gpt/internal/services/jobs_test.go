@@ -0,0 +1,18 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+)
+
+func TestDigestJobKey(t *testing.T) {
+	a, b, c := model.RawNews{ID: uuid.New()}, model.RawNews{ID: uuid.New()}, model.RawNews{ID: uuid.New()}
+
+	key := digestJobKey([]model.RawNews{a, b, c})
+	require.Equal(t, key, digestJobKey([]model.RawNews{c, a, b}), "key depends on the order")
+	require.NotEqual(t, key, digestJobKey([]model.RawNews{a, b}), "different batches have the same key")
+}
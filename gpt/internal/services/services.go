@@ -1,14 +1,13 @@
 package services
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/text/language"
@@ -19,10 +18,9 @@ import (
 	"common/data"
 	"common/data/model"
 	"common/data/store"
-	"common/iteration"
-	"common/math"
 	"gpt/internal/bot"
 	"gpt/internal/config"
+	"gpt/internal/translator"
 )
 
 const (
@@ -51,7 +49,8 @@ func New(cfg config.Config) Service {
 
 func (s service) Run(ctx context.Context) error {
 	s.log.Info("Staring gpt generator bot service...")
-	summarizationBot := bot.NewOpenAI(s.cfg)
+	summarizationBot := bot.New(s.cfg)
+	digestTranslator := translator.New(s.cfg, summarizationBot)
 
 	common.RunEveryWithBackoff(s.cfg.GenerateEvery(), 15*time.Second, 15*time.Minute, func() error {
 		s.log.Debug("Generating digest...")
@@ -62,7 +61,9 @@ func (s service) Run(ctx context.Context) error {
 		}
 
 		for i := 0; i < int(totalRows)/processingLimit+1; i++ {
-			rawNews, err := s.dataProvider.RawNewsProvider().Order("id", data.OrderAsc).Limit(processingLimit).Offset(uint64(i * processingLimit)).Select(ctx)
+			// processed batches are removed, so the oldest batch is always the first, and a failed batch is retried as it was,
+			// newer raw news go after it and can't change its idempotency key
+			rawNews, err := s.dataProvider.RawNewsProvider().Order("created_at", data.OrderAsc).Order("id", data.OrderAsc).Limit(processingLimit).Select(ctx)
 			if err != nil {
 				if !errors.Is(err, data.ErrNotFound) {
 					return errors.Wrap(err, "failed to count raw news")
@@ -71,45 +72,8 @@ func (s service) Run(ctx context.Context) error {
 				return nil
 			}
 
-			titleIDs := iteration.Map(rawNews, func(t model.RawNews) uuid.UUID {
-				return t.TitleID
-			})
-
-			titles, err := s.dataProvider.TitlesProvider().ByIDs(titleIDs).Select(ctx)
-
-			aggregatedTextBuf := bytes.NewBuffer(make([]byte, 0, 1000))
-
-			for _, rawNewsPiece := range rawNews {
-				aggregatedTextBuf.WriteString(convert.FromPtr(rawNewsPiece.Body))
-			}
-
-			for _, locale := range s.cfg.Locales() {
-				s.log.WithField("locale", locale).Debug("Generating for locale")
-
-				timestamp := common.CurrentTimestamp()
-
-				news, digestResponse, err := s.generateDigestForLocale(
-					ctx,
-					summarizationBot,
-					s.cfg.QueryContext(), aggregatedTextBuf.String(), locale,
-					titles,
-					timestamp,
-				)
-				if err != nil {
-					return errors.Wrapf(err, "failed to generate for locale: %s", locale)
-				}
-
-				if err := s.addNews(ctx, news, digestResponse); err != nil {
-					return errors.Wrap(err, "failed to add news")
-				}
-			}
-
-			rawNewsIDs := iteration.Map(rawNews, func(t model.RawNews) uuid.UUID {
-				return t.ID
-			})
-
-			if err := s.dataProvider.RawNewsProvider().ByIDs(rawNewsIDs).Remove(ctx, model.RawNews{}); err != nil {
-				return errors.Wrap(err, "failed to remove processed raw news")
+			if err := s.processBatch(ctx, summarizationBot, digestTranslator, rawNews); err != nil {
+				return errors.Wrap(err, "failed to process raw news batch")
 			}
 		}
 
@@ -121,35 +85,63 @@ func (s service) Run(ctx context.Context) error {
 	return nil
 }
 
-func (s service) generateDigestForLocale(ctx context.Context,
-	bot bot.Bot,
-	queryContext, aggregatedText, locale string,
-	titles []model.Title,
-	timestamp time.Time) (*model.News, []model.Coin, error) {
+// generateDigest asks for the digest in the pivot locale, sources are cited by their position in the titles starting from 1
+func (s service) generateDigest(ctx context.Context, bot bot.Bot, queryContext, aggregatedText string, sourcesCount int) ([]model.NewsItem, error) {
 	// we shouldn't create single post longer than 10 minutes, if that happens - probably something went wrong
 	deadlineCtx, cancel := context.WithDeadline(ctx, time.Now().Add(10*time.Minute))
 	defer cancel()
 
-	lang := display.English.Tags().Name(language.Make(locale))
+	lang := display.English.Tags().Name(language.Make(s.cfg.PivotLocale()))
+
+	replyMsg, err := bot.AskFunction(deadlineCtx, aggregatedText, queryContext, lang, digestFunction)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to ask bot")
+	}
+
+	sourceIDs := make(map[int]bool, sourcesCount)
+	for i := 1; i <= sourcesCount; i++ {
+		sourceIDs[i] = true
+	}
 
-	replyMsg, err := bot.Ask(deadlineCtx, aggregatedText[:math.Min(len(aggregatedText), maxInputChars)], queryContext, lang)
+	items, invalid, err := parseDigest(replyMsg.Text, sourceIDs)
+	for _, err := range invalid {
+		s.log.WithError(err).Warn("invalid digest item, skipping...")
+	}
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to ask bot")
+		return nil, errors.Wrap(err, "failed to parse digest")
+	}
+
+	return items, nil
+}
+
+// translateDigest the pivot locale gets the digest as it was generated
+func (s service) translateDigest(ctx context.Context, t translator.Translator, items []model.NewsItem, locale string) ([]model.NewsItem, error) {
+	if strings.EqualFold(locale, s.cfg.PivotLocale()) {
+		return items, nil
 	}
 
-	content, coins := parseCoins(replyMsg.Text)
+	deadlineCtx, cancel := context.WithDeadline(ctx, time.Now().Add(5*time.Minute))
+	defer cancel()
+
+	return t.Translate(deadlineCtx, items, locale)
+}
 
-	resourcesList := make([]model.NewsMediaResource, 0, len(titles))
+// digestMedia media of the pivot digest, localized news only differ by the text
+func digestMedia(items []model.NewsItem,
+	titles []model.Title,
+	images []model.NewsMediaResource,
+	timestamp time.Time) (*model.NewsMedia, error) {
+	resourcesList := make([]model.NewsMediaResource, 0, len(titles)+len(images))
 	for i, title := range titles {
 		metaLinks := model.MetaLinksData{
-			ID:    strconv.Itoa(i),
+			ID:    strconv.Itoa(i + 1),
 			URL:   convert.FromPtr(title.URL),
 			Title: convert.FromPtr(title.Title),
 		}
 
 		metaLinksBody, err := json.Marshal(metaLinks)
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "failed to marshal meta sources body")
+			return nil, errors.Wrap(err, "failed to marshal meta sources body")
 		}
 
 		resourcesList = append(resourcesList, model.NewsMediaResource{
@@ -158,52 +150,57 @@ func (s service) generateDigestForLocale(ctx context.Context,
 			Meta: metaLinksBody,
 		})
 	}
+	resourcesList = append(resourcesList, images...)
+
+	return &model.NewsMedia{
+		Title:     convert.ToPtr(fmt.Sprintf("Digest hour: %d, Day: %d", timestamp.Hour(), timestamp.Day())),
+		Text:      convert.ToPtr(digestText(items)),
+		Resources: resourcesList,
+		Items:     items,
+	}, nil
+}
 
-	news := &model.News{
-		Locale: convert.ToPtr(locale),
-		Media: &model.NewsMedia{
-			Title:     convert.ToPtr(fmt.Sprintf("Digest hour: %d, Day: %d", timestamp.Hour(), timestamp.Day())),
-			Text:      convert.ToPtr(content),
-			Resources: resourcesList,
-		},
-		Source: convert.ToPtr("gpt-bing"),
-		Status: convert.ToPtr(model.StatusPending),
+func newsForLocale(job *model.DigestJob, locale string, items []model.NewsItem) *model.News {
+	media := *job.Media
+	media.Text = convert.ToPtr(digestText(items))
+	media.Items = items
+
+	return &model.News{
+		DigestID: convert.ToPtr(job.DigestID),
+		Locale:   convert.ToPtr(locale),
+		Media:    &media,
+		Source:   convert.ToPtr("gpt-bing"),
+		Status:   convert.ToPtr(model.StatusPending),
 	}
-
-	s.log.WithFields(logrus.Fields{
-		"digest-hour": timestamp.Hour(),
-		"digest-day":  timestamp.Day(),
-	}).Debug("Finished generating")
-
-	return news, coins, nil
 }
 
-func (s service) addNews(ctx context.Context, news *model.News, coins []model.Coin) error {
-	createdNews, err := s.dataProvider.NewsProvider().Insert(ctx, convert.FromPtr(news))
+// addNews inserts the news with its coins and channels, dp is expected to be in transaction
+func addNews(ctx context.Context, dp store.DataProvider, news *model.News, coins []model.Coin) (*model.News, error) {
+	createdNews, err := dp.NewsProvider().Insert(ctx, convert.FromPtr(news))
 	if err != nil {
-		return errors.Wrap(err, "failed to insert news digest")
+		return nil, errors.Wrap(err, "failed to insert news digest")
 	}
 
 	newsCoinsBatch := createCoinsNewsCoinsBatch(createdNews.ID, coins)
-	if err = s.dataProvider.CoinsProvider().UpsertCoinsBatch(ctx, coins); err != nil {
-		return errors.Wrap(err, "failed to insert batch of coins")
+	if err = dp.CoinsProvider().UpsertCoinsBatch(ctx, coins); err != nil {
+		return nil, errors.Wrap(err, "failed to insert batch of coins")
 	}
 
-	if err = s.dataProvider.NewsCoinsProvider().InsertBatch(ctx, newsCoinsBatch); err != nil {
-		return errors.Wrap(err, "failed to insert batch of news-coins")
+	if err = dp.NewsCoinsProvider().InsertBatch(ctx, newsCoinsBatch); err != nil {
+		return nil, errors.Wrap(err, "failed to insert batch of news-coins")
 	}
 
-	channels, err := s.dataProvider.ChannelsProvider().Select(ctx)
+	channels, err := dp.ChannelsProvider().Select(ctx)
 	if err != nil {
 		if !errors.Is(err, data.ErrNotFound) {
-			return errors.Wrap(err, "failed to select channels")
+			return nil, errors.Wrap(err, "failed to select channels")
 		}
 	}
 
 	newsChannelsBatch := toNewsChannelsBatch(createdNews, channels)
-	if err = s.dataProvider.NewsChannelsProvider().InsertBatch(ctx, newsChannelsBatch); err != nil {
-		return errors.Wrap(err, "failed to insert batch of news-channels")
+	if err = dp.NewsChannelsProvider().InsertBatch(ctx, newsChannelsBatch); err != nil {
+		return nil, errors.Wrap(err, "failed to insert batch of news-channels")
 	}
 
-	return nil
+	return createdNews, nil
 }
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	"common/convert"
+	"common/data"
+	"common/data/model"
+	"common/iteration"
+)
+
+// story single piece of news, body of the canonical title and alternates syndicated by other sources
+type story struct {
+	canonical  model.Title
+	alternates []model.Title
+	body       string
+	summary    string
+	meta       model.RawNewsMeta
+	language   string
+}
+
+func (s story) titles() []model.Title {
+	return append([]model.Title{s.canonical}, s.alternates...)
+}
+
+// collectStories pairs raw news with their titles and near-duplicate alternates, raw news are only stored for canonical titles
+func (s service) collectStories(ctx context.Context, rawNews []model.RawNews) ([]story, error) {
+	titleIDs := iteration.Map(rawNews, func(t model.RawNews) uuid.UUID {
+		return t.TitleID
+	})
+
+	titles, err := s.dataProvider.TitlesProvider().ByIDs(titleIDs).Select(ctx)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		return nil, errors.Wrap(err, "failed to select raw news titles")
+	}
+
+	titlesByID := make(map[uuid.UUID]model.Title, len(titles))
+	storyIDs := make([]uuid.UUID, 0, len(titles))
+	for _, t := range titles {
+		titlesByID[t.ID] = t
+		if t.StoryID != nil {
+			storyIDs = append(storyIDs, *t.StoryID)
+		}
+	}
+
+	// story id : alternates
+	alternates := make(map[uuid.UUID][]model.Title)
+	if len(storyIDs) > 0 {
+		storyTitles, err := s.dataProvider.TitlesProvider().ByStoryIDs(storyIDs).Select(ctx)
+		if err != nil && !errors.Is(err, data.ErrNotFound) {
+			return nil, errors.Wrap(err, "failed to select story titles")
+		}
+
+		for _, t := range storyTitles {
+			if _, ok := titlesByID[t.ID]; ok {
+				continue
+			}
+			alternates[*t.StoryID] = append(alternates[*t.StoryID], t)
+		}
+	}
+
+	stories := make([]story, 0, len(rawNews))
+	for _, r := range rawNews {
+		title, ok := titlesByID[r.TitleID]
+		if !ok {
+			s.log.WithField("title-id", r.TitleID).Warn("raw news title not found...")
+			continue
+		}
+
+		st := story{
+			canonical: title,
+			body:      convert.FromPtr(r.Body),
+			meta:      convert.FromPtr(r.Meta),
+			language:  convert.FromPtr(r.Language),
+		}
+		// canonical url is the stable address of the article, tracking params and mirrors are dropped
+		if st.meta.CanonicalURL != "" {
+			st.canonical.URL = convert.ToPtr(st.meta.CanonicalURL)
+		}
+		if title.StoryID != nil {
+			st.alternates = alternates[*title.StoryID]
+		}
+		stories = append(stories, st)
+	}
+
+	// stories are told in the order they happened, undated ones go last
+	sort.SliceStable(stories, func(i, j int) bool {
+		ri, rj := stories[i].canonical.ReleaseDate, stories[j].canonical.ReleaseDate
+		if ri == nil || rj == nil {
+			return ri != nil
+		}
+		return ri.Before(*rj)
+	})
+	return groupByLanguage(stories), nil
+}
+
+// groupByLanguage keeps stories of the same language together, the most common language goes first
+// and the undetected one last, stories keep their order within the group
+func groupByLanguage(stories []story) []story {
+	counts := make(map[string]int)
+	for _, st := range stories {
+		counts[st.lang()]++
+	}
+
+	sort.SliceStable(stories, func(i, j int) bool {
+		li, lj := stories[i].lang(), stories[j].lang()
+		if li == lj {
+			return false
+		}
+		if li == model.LanguageUndetermined || lj == model.LanguageUndetermined {
+			return lj == model.LanguageUndetermined
+		}
+		if counts[li] != counts[lj] {
+			return counts[li] > counts[lj]
+		}
+		return li < lj
+	})
+	return stories
+}
+
+// lang raw news stored before the language detection have no language
+func (s story) lang() string {
+	if s.language == "" {
+		return model.LanguageUndetermined
+	}
+	return s.language
+}
+
+const (
+	communityLabel = "Community post"
+	communityNote  = "Community posts are opinions of forum users, report them as the community sentiment rather than as facts.\n\n"
+)
+
+// label community posts are opinions of the readers rather than reported facts, the model has to tell them apart
+func (s story) label() string {
+	if convert.FromPtr(s.canonical.Kind) == model.KindCommunity {
+		return communityLabel
+	}
+	return "Story"
+}
+
+// aggregateStories joins story summaries into the prompt, each story goes once with all the sources that reported it,
+// stories are headed by their language, so the model knows which of them have to be translated
+func aggregateStories(stories []story) string {
+	var b strings.Builder
+	// the note goes first, so that condensing the summaries doesn't lose it
+	for _, st := range stories {
+		if st.label() == communityLabel {
+			b.WriteString(communityNote)
+			break
+		}
+	}
+
+	lastLang, sourceID := "", 0
+	for i, st := range stories {
+		if st.lang() != lastLang {
+			lastLang = st.lang()
+			fmt.Fprintf(&b, "Stories in %s:\n\n", languageName(lastLang))
+		}
+
+		// sources are numbered the way storySources lists them, the digest cites them by these ids
+		sources := make([]string, 0, len(st.titles()))
+		for _, t := range st.titles() {
+			sourceID++
+			sources = append(sources, fmt.Sprintf("[%d] %s", sourceID, convert.FromPtr(t.Source)))
+		}
+
+		fmt.Fprintf(&b, "%s %d (sources: %s):\n%s\n\n", st.label(), i+1, strings.Join(sources, ", "), st.summary)
+	}
+	return b.String()
+}
+
+// storySources titles of all the stories, source id is the position in the list starting from 1
+func storySources(stories []story) []model.Title {
+	titles := make([]model.Title, 0, len(stories))
+	for _, st := range stories {
+		titles = append(titles, st.titles()...)
+	}
+	return titles
+}
+
+func languageName(code string) string {
+	if code == model.LanguageUndetermined {
+		return "unknown language"
+	}
+	return display.English.Languages().Name(language.Make(code))
+}
+
+// storiesImages picks the best image of every story, so the digest is illustrated by as many stories as possible
+func storiesImages(stories []story, limit int) []model.NewsMediaResource {
+	seen := make(map[string]bool, len(stories))
+	images := make([]model.NewsMediaResource, 0, limit)
+	for _, st := range stories {
+		if len(images) >= limit {
+			break
+		}
+		for _, image := range st.meta.Images {
+			if seen[image] {
+				continue
+			}
+			seen[image] = true
+
+			images = append(images, model.NewsMediaResource{
+				Type: convert.ToPtr(model.ResourceTypeImage),
+				URL:  convert.ToPtr(image),
+			})
+			break
+		}
+	}
+	return images
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common/convert"
+	"common/data/model"
+	"gpt/internal/bot"
+)
+
+const (
+	// shortStoryTokens stories this short are taken as they are, summarizing them would not save anything
+	shortStoryTokens = 200
+	// summaryTimeout summary of a single story, including its chunks
+	summaryTimeout = 5 * time.Minute
+	// maxReduceRounds every round condenses the summaries, if it doesn't fit by then - something went wrong
+	maxReduceRounds = 3
+
+	summaryContext = "You summarize a single news article for a news digest. " +
+		"Keep the names, numbers and dates, attribute claims to whoever made them and do not add anything that is not in the text."
+	summaryPrompt      = "Summarize the article below in up to 5 sentences:\n\n%s"
+	chunkSummaryPrompt = "Summarize part %d of %d of the article below in up to 5 sentences:\n\n%s"
+	combinePrompt      = "Below are the summaries of the consecutive parts of a single article, " +
+		"combine them into a single summary of up to 5 sentences:\n\n%s"
+
+	condenseContext = "You condense the summaries of news stories for a news digest. " +
+		"Keep every story and its heading line as is, shorten the text below the heading to 1-2 sentences."
+)
+
+// summarizeStories map step, every story is summarized on its own, so none of them is cut from the digest prompt,
+// a story the bot failed to summarize fails the batch, so it is retried as a whole rather than lost with its raw news
+func (s service) summarizeStories(ctx context.Context, bot bot.Bot, stories []story) ([]story, error) {
+	summarized := make([]story, 0, len(stories))
+	for _, st := range stories {
+		summary, err := s.summarizeStory(ctx, bot, st)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to summarize story: %s", convert.FromPtr(st.canonical.URL))
+		}
+		st.summary = summary
+		summarized = append(summarized, st)
+	}
+	return summarized, nil
+}
+
+func (s service) summarizeStory(ctx context.Context, bot bot.Bot, st story) (string, error) {
+	body := strings.TrimSpace(st.body)
+	if estimateTokens(body) <= shortStoryTokens {
+		return body, nil
+	}
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, summaryTimeout)
+	defer cancel()
+
+	// summaries are written in the language of the story, it is translated once by the digest
+	lang := languageName(st.lang())
+	if st.lang() == model.LanguageUndetermined {
+		lang = "English"
+	}
+
+	chunks := chunkText(body, s.cfg.ChunkTokens())
+	if len(chunks) == 1 {
+		return s.ask(deadlineCtx, bot, fmt.Sprintf(summaryPrompt, chunks[0]), summaryContext, lang)
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"url":    convert.FromPtr(st.canonical.URL),
+		"chunks": len(chunks),
+	}).Debug("Summarizing long story by chunks...")
+
+	summaries := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		summary, err := s.ask(deadlineCtx, bot, fmt.Sprintf(chunkSummaryPrompt, i+1, len(chunks), chunk), summaryContext, lang)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to summarize chunk %d of %d", i+1, len(chunks))
+		}
+		summaries[i] = summary
+	}
+
+	return s.ask(deadlineCtx, bot, fmt.Sprintf(combinePrompt, strings.Join(summaries, "\n\n")), summaryContext, lang)
+}
+
+// reduceStories joins story summaries into the digest prompt, when they don't fit the model input
+// they are condensed batch by batch, so every story still reaches the digest
+func (s service) reduceStories(ctx context.Context, bot bot.Bot, stories []story) (string, error) {
+	aggregatedText := aggregateStories(stories)
+	maxTokens := s.cfg.MaxInputTokens()
+
+	for round := 1; estimateTokens(aggregatedText) > maxTokens; round++ {
+		if round > maxReduceRounds {
+			return "", errors.Errorf("stories don't fit the input after %d rounds, tokens: %d", maxReduceRounds, estimateTokens(aggregatedText))
+		}
+
+		s.log.WithFields(logrus.Fields{
+			"round":  round,
+			"tokens": estimateTokens(aggregatedText),
+		}).Debug("Condensing story summaries...")
+
+		// batches are half of the input, so the reply fits together with the prompt
+		batches := chunkText(aggregatedText, maxTokens/2)
+		condensed := make([]string, len(batches))
+		for i, batch := range batches {
+			// condensed summaries are in English, the digest translates them to the locale anyway
+			reply, err := s.ask(ctx, bot, batch, condenseContext, "English")
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to condense batch %d of %d", i+1, len(batches))
+			}
+			condensed[i] = reply
+		}
+		aggregatedText = strings.Join(condensed, "\n\n")
+	}
+	return aggregatedText, nil
+}
+
+func (s service) ask(ctx context.Context, bot bot.Bot, prompt, context, language string) (string, error) {
+	reply, err := bot.Ask(ctx, prompt, context, language)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to ask bot")
+	}
+	text := strings.TrimSpace(reply.Text)
+	if text == "" {
+		return "", errors.New("bot replied with empty text")
+	}
+	return text, nil
+}
@@ -1,37 +1,13 @@
 package services
 
 import (
-	"regexp"
-	"strings"
-
 	"github.com/google/uuid"
 
 	"common/data/model"
 )
 
-const maxInputChars = 45000
-
-var coinsRegex = regexp.MustCompile(`\<coins\>\[([A-Z1-9\,\s]+)\]\<\/coins\>`)
-
-func parseCoins(content string) (string, []model.Coin) {
-	coinsSet := make(map[string]bool)
-	for _, match := range coinsRegex.FindAllStringSubmatch(content, -1) {
-		for _, coin := range strings.Split(match[1], ",") {
-			coinsSet[strings.TrimSpace(coin)] = true
-		}
-	}
-
-	coins := make([]model.Coin, 0, len(coinsSet))
-
-	for k := range coinsSet {
-		coins = append(coins, model.Coin{
-			Code: k,
-			Slug: k,
-		})
-	}
-
-	return coinsRegex.ReplaceAllString(content, ""), coins
-}
+// maxDigestImages telegram media group holds up to 10, a few pictures are enough for a digest
+const maxDigestImages = 4
 
 func toNewsChannelsBatch(news *model.News, channels []model.Channel) []model.NewsChannel {
 	newsChannels := make([]model.NewsChannel, len(channels))
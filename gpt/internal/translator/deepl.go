@@ -0,0 +1,116 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common/data/model"
+	"gpt/internal/config"
+)
+
+const (
+	deeplURL     = "https://api.deepl.com"
+	deeplFreeURL = "https://api-free.deepl.com"
+	// deeplFreeSuffix keys of the free plan only work with the free API host
+	deeplFreeSuffix = ":fx"
+)
+
+// deeplTargets DeepL deprecated the targets without the variant for these languages
+var deeplTargets = map[string]string{
+	"en": "EN-US",
+	"pt": "PT-BR",
+}
+
+type deeplRequest struct {
+	Text       []string `json:"text"`
+	TargetLang string   `json:"target_lang"`
+}
+
+type deeplResponse struct {
+	Translations []struct {
+		Text string `json:"text"`
+	} `json:"translations"`
+	Message string `json:"message"`
+}
+
+// deeplTranslator translates the digest with the DeepL API, all the texts of the digest go in a single request
+type deeplTranslator struct {
+	log *logrus.Entry
+
+	authToken string
+	baseURL   string
+
+	client *http.Client
+}
+
+func NewDeepL(cfg config.Config, translatorCfg config.TranslatorConfig) Translator {
+	baseURL := translatorCfg.BaseURL
+	if baseURL == "" {
+		baseURL = deeplURL
+		if strings.HasSuffix(translatorCfg.AuthToken, deeplFreeSuffix) {
+			baseURL = deeplFreeURL
+		}
+	}
+
+	return &deeplTranslator{
+		log: cfg.Logging().WithField("service", "[DEEPL-TRANSLATOR]"),
+
+		authToken: translatorCfg.AuthToken,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+
+		client: &http.Client{},
+	}
+}
+
+func (t deeplTranslator) Translate(ctx context.Context, items []model.NewsItem, locale string) ([]model.NewsItem, error) {
+	reqBody, err := json.Marshal(deeplRequest{
+		Text:       texts(items),
+		TargetLang: deeplTarget(locale),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal translate request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/v2/translate", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create translate request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.authToken)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send translate request")
+	}
+	defer resp.Body.Close()
+
+	var respBody deeplResponse
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode translate response, status code: %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("translate request failed: %d %s", resp.StatusCode, respBody.Message)
+	}
+
+	translatedTexts := make([]string, len(respBody.Translations))
+	for i, tr := range respBody.Translations {
+		translatedTexts[i] = strings.TrimSpace(tr.Text)
+	}
+
+	t.log.WithField("locale", locale).Debugf("Translated %d items", len(items))
+	return translated(items, translatedTexts)
+}
+
+func deeplTarget(locale string) string {
+	locale = strings.ToLower(locale)
+	if target, ok := deeplTargets[locale]; ok {
+		return target
+	}
+	return strings.ToUpper(locale)
+}
@@ -0,0 +1,98 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sashabaranov/go-openai/jsonschema"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	"common/data/model"
+	"gpt/internal/bot"
+	"gpt/internal/config"
+)
+
+const llmContext = "You translate the items of a news digest. " +
+	"Translate every item, keep their order, keep the tickers, names and numbers as they are."
+
+var translateFunction = bot.Function{
+	Name:        "publish_translation",
+	Description: "Publish the translated digest items",
+	Parameters: jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"items": {
+				Type: jsonschema.Array,
+				Items: &jsonschema.Definition{
+					Type: jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{
+						"headline": {Type: jsonschema.String},
+						"body":     {Type: jsonschema.String},
+					},
+					Required: []string{"headline", "body"},
+				},
+			},
+		},
+		Required: []string{"items"},
+	},
+}
+
+type llmItems struct {
+	Items []struct {
+		Headline string `json:"headline"`
+		Body     string `json:"body"`
+	} `json:"items"`
+}
+
+// llmTranslator asks the model to translate the generated digest, much cheaper than generating it again
+type llmTranslator struct {
+	log *logrus.Entry
+
+	bot bot.Bot
+}
+
+func NewLLM(cfg config.Config, b bot.Bot) Translator {
+	return &llmTranslator{
+		log: cfg.Logging().WithField("service", "[LLM-TRANSLATOR]"),
+		bot: b,
+	}
+}
+
+func (t llmTranslator) Translate(ctx context.Context, items []model.NewsItem, locale string) ([]model.NewsItem, error) {
+	var source llmItems
+	for _, item := range items {
+		source.Items = append(source.Items, struct {
+			Headline string `json:"headline"`
+			Body     string `json:"body"`
+		}{item.Headline, item.Body})
+	}
+
+	prompt, err := json.Marshal(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal items to translate")
+	}
+
+	lang := display.English.Tags().Name(language.Make(locale))
+
+	reply, err := t.bot.AskFunction(ctx, string(prompt), llmContext, lang, translateFunction)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to ask bot")
+	}
+
+	var target llmItems
+	if err := json.Unmarshal([]byte(reply.Text), &target); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal translated items")
+	}
+
+	texts := make([]string, 0, 2*len(target.Items))
+	for _, item := range target.Items {
+		texts = append(texts, strings.TrimSpace(item.Headline), strings.TrimSpace(item.Body))
+	}
+
+	t.log.WithField("locale", locale).Debugf("Translated %d items", len(target.Items))
+	return translated(items, texts)
+}
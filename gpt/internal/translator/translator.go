@@ -0,0 +1,55 @@
+package translator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"common/data/model"
+	"gpt/internal/bot"
+	"gpt/internal/config"
+)
+
+// Translator translates headlines and bodies of the digest items, the rest of the item is kept as is
+type Translator interface {
+	Translate(ctx context.Context, items []model.NewsItem, locale string) ([]model.NewsItem, error)
+}
+
+// New builds the translator selected in the gpt config, the llm one reuses the digest bot
+func New(cfg config.Config, b bot.Bot) Translator {
+	translatorCfg := cfg.Translator()
+
+	switch translatorCfg.Type {
+	case config.TranslatorLLM:
+		return NewLLM(cfg, b)
+	case config.TranslatorDeepL:
+		return NewDeepL(cfg, translatorCfg)
+	default:
+		panic(errors.Errorf("unknown translator type: %s", translatorCfg.Type))
+	}
+}
+
+// translated copies of the items with the translated texts, texts go as headline, body pairs
+func translated(items []model.NewsItem, texts []string) ([]model.NewsItem, error) {
+	if len(texts) != 2*len(items) {
+		return nil, errors.Errorf("translated %d texts of %d", len(texts), 2*len(items))
+	}
+
+	result := make([]model.NewsItem, len(items))
+	for i, item := range items {
+		item.Headline, item.Body = texts[2*i], texts[2*i+1]
+		if item.Headline == "" || item.Body == "" {
+			return nil, errors.Errorf("item %d translated to empty text", i+1)
+		}
+		result[i] = item
+	}
+	return result, nil
+}
+
+func texts(items []model.NewsItem) []string {
+	texts := make([]string, 0, 2*len(items))
+	for _, item := range items {
+		texts = append(texts, item.Headline, item.Body)
+	}
+	return texts
+}
@@ -0,0 +1,94 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+	"gpt/internal/bot"
+)
+
+var items = []model.NewsItem{
+	{Headline: "ETF delayed", Body: "The SEC postponed the decision.", Sources: []string{"1"}, Coins: []string{"BTC"}, Sentiment: model.SentimentNegative, Importance: 3},
+	{Headline: "Upgrade", Body: "The upgrade went live.", Sources: []string{"2"}, Coins: []string{"ETH"}, Sentiment: model.SentimentPositive, Importance: 5},
+}
+
+var log = logrus.NewEntry(logrus.New())
+
+type stubBot struct {
+	reply  string
+	prompt string
+}
+
+func (b *stubBot) Ask(context.Context, string, string, string) (*bot.Message, error) {
+	return nil, nil
+}
+
+func (b *stubBot) AskFunction(_ context.Context, prompt, _, _ string, _ bot.Function) (*bot.Message, error) {
+	b.prompt = prompt
+	return &bot.Message{Text: b.reply}, nil
+}
+
+func TestLLMTranslate(t *testing.T) {
+	b := &stubBot{reply: `{"items": [
+	  {"headline": "ETF verschoben", "body": "Die SEC hat die Entscheidung verschoben."},
+	  {"headline": "Upgrade", "body": "Das Upgrade ist live."}]}`}
+
+	translator := llmTranslator{log: log, bot: b}
+
+	got, err := translator.Translate(context.Background(), items, "de")
+	require.NoError(t, err)
+
+	want := []model.NewsItem{items[0], items[1]}
+	want[0].Headline, want[0].Body = "ETF verschoben", "Die SEC hat die Entscheidung verschoben."
+	want[1].Body = "Das Upgrade ist live."
+	require.Equal(t, want, got)
+
+	// a dropped item is not silently shifted onto the other
+	b.reply = `{"items": [{"headline": "ETF verschoben", "body": "Die SEC hat die Entscheidung verschoben."}]}`
+	_, err = translator.Translate(context.Background(), items, "de")
+	require.Error(t, err, "partial translation is accepted")
+}
+
+func TestDeepLTranslate(t *testing.T) {
+	var targetLang string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/translate" || r.Header.Get("Authorization") != "DeepL-Auth-Key token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "forbidden"}`))
+			return
+		}
+
+		var req deeplRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		targetLang = req.TargetLang
+
+		var resp deeplResponse
+		for _, text := range req.Text {
+			resp.Translations = append(resp.Translations, struct {
+				Text string `json:"text"`
+			}{"pt: " + text})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	translator := deeplTranslator{log: log, authToken: "token", baseURL: server.URL, client: server.Client()}
+
+	got, err := translator.Translate(context.Background(), items, "pt")
+	require.NoError(t, err)
+	require.Equal(t, "PT-BR", targetLang)
+	require.Equal(t, "pt: Upgrade", got[1].Headline)
+	require.Equal(t, "pt: The upgrade went live.", got[1].Body)
+	require.Equal(t, 5, got[1].Importance)
+	require.Equal(t, "Upgrade", items[1].Headline, "pivot items are modified")
+}
@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS titles_coins
+(
+    id       uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    title_id uuid NOT NULL REFERENCES titles (id),
+    code     text NOT NULL REFERENCES coins (code),
+    UNIQUE (title_id, code)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS titles_coins;
\ No newline at end of file
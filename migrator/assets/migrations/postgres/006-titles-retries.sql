@@ -0,0 +1,24 @@
+-- +migrate Up
+ALTER TABLE titles
+    ADD COLUMN IF NOT EXISTS attempts        integer DEFAULT 0 NOT NULL,
+    ADD COLUMN IF NOT EXISTS last_error      text,
+    ADD COLUMN IF NOT EXISTS next_attempt_at timestamp;
+
+CREATE INDEX IF NOT EXISTS titles_status_next_attempt_at_idx ON titles (status, next_attempt_at);
+
+-- dead titles can be purged, their coins links go with them
+ALTER TABLE titles_coins
+    DROP CONSTRAINT IF EXISTS titles_coins_title_id_fkey,
+    ADD CONSTRAINT titles_coins_title_id_fkey FOREIGN KEY (title_id) REFERENCES titles (id) ON DELETE CASCADE;
+
+-- +migrate Down
+ALTER TABLE titles_coins
+    DROP CONSTRAINT IF EXISTS titles_coins_title_id_fkey,
+    ADD CONSTRAINT titles_coins_title_id_fkey FOREIGN KEY (title_id) REFERENCES titles (id);
+
+DROP INDEX IF EXISTS titles_status_next_attempt_at_idx;
+
+ALTER TABLE titles
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS stories
+(
+    id                 uuid      DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at         timestamp DEFAULT now(),
+    canonical_title_id uuid   NOT NULL REFERENCES titles (id) ON DELETE CASCADE,
+    simhash            bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS stories_created_at_idx ON stories (created_at);
+
+ALTER TABLE titles
+    ADD COLUMN IF NOT EXISTS story_id uuid REFERENCES stories (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS titles_story_id_idx ON titles (story_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS titles_story_id_idx;
+
+ALTER TABLE titles
+    DROP COLUMN IF EXISTS story_id;
+
+DROP TABLE IF EXISTS stories;
\ No newline at end of file
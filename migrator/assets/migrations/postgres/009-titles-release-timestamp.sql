@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE titles
+    ALTER COLUMN release_date TYPE timestamptz USING release_date::timestamp AT TIME ZONE 'UTC';
+
+-- +migrate Down
+ALTER TABLE titles
+    ALTER COLUMN release_date TYPE date USING (release_date AT TIME ZONE 'UTC')::date;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE raw_news
+    ADD COLUMN IF NOT EXISTS language text;
+
+-- +migrate Down
+ALTER TABLE raw_news
+    DROP COLUMN IF EXISTS language;
\ No newline at end of file
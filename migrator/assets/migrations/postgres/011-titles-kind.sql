@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE titles
+    ADD COLUMN IF NOT EXISTS kind text DEFAULT 'news' NOT NULL;
+
+-- +migrate Down
+ALTER TABLE titles
+    DROP COLUMN IF EXISTS kind;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE news
+    ADD COLUMN IF NOT EXISTS digest_id uuid;
+
+CREATE INDEX IF NOT EXISTS news_digest_id_idx ON news (digest_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS news_digest_id_idx;
+
+ALTER TABLE news
+    DROP COLUMN IF EXISTS digest_id;
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS digest_jobs
+(
+    id              uuid      DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at      timestamp DEFAULT now(),
+    updated_at      timestamp,
+    idempotency_key text      NOT NULL UNIQUE,
+    digest_id       uuid      NOT NULL,
+    media           jsonb,
+    status          text
+);
+
+CREATE TABLE IF NOT EXISTS digest_job_locales
+(
+    id         uuid      DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at timestamp DEFAULT now(),
+    job_id     uuid      NOT NULL REFERENCES digest_jobs (id) ON DELETE CASCADE,
+    locale     text      NOT NULL,
+    news_id    uuid      NOT NULL REFERENCES news (id) ON DELETE CASCADE,
+    UNIQUE (job_id, locale)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS digest_job_locales;
+
+DROP TABLE IF EXISTS digest_jobs;
\ No newline at end of file
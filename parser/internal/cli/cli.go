@@ -1,13 +1,19 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"runtime/debug"
 
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 
 	"parser/internal/config"
 	"parser/internal/services"
+	"parser/internal/services/dead"
 )
 
 func Run(args []string) bool {
@@ -23,6 +29,17 @@ func Run(args []string) bool {
 	}()
 
 	svc := services.NewService(cfg)
+	deadSvc := dead.New(cfg)
+
+	persistFlag := &cli.BoolFlag{
+		Name:  "persist",
+		Usage: "store the results in the database",
+	}
+
+	idsFlag := &cli.StringSliceFlag{
+		Name:  "id",
+		Usage: "title id, all dead titles if not set",
+	}
 
 	app := &cli.App{
 		Commands: cli.Commands{
@@ -33,6 +50,87 @@ func Run(args []string) bool {
 					return svc.Run(c.Context)
 				},
 			},
+			{
+				Name:  "crawl",
+				Usage: "run a single crawler once and print the crawled titles",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "source",
+						Usage:    "crawler name from the crawlers config section",
+						Required: true,
+					},
+					persistFlag,
+				},
+				Action: func(c *cli.Context) error {
+					titles, err := svc.CrawlSource(c.Context, c.String("source"), c.Bool(persistFlag.Name))
+					if err != nil {
+						return err
+					}
+					return printJSON(os.Stdout, titles)
+				},
+			},
+			{
+				Name:  "extract",
+				Usage: "crawl and extract a single page once and print the raw news",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "url",
+						Usage:    "page url, its title has to be stored to persist the raw news",
+						Required: true,
+					},
+					persistFlag,
+				},
+				Action: func(c *cli.Context) error {
+					rawNews, err := svc.ExtractURL(c.Context, c.String("url"), c.Bool(persistFlag.Name))
+					if err != nil {
+						return err
+					}
+					return printJSON(os.Stdout, rawNews)
+				},
+			},
+			{
+				Name:  "dead",
+				Usage: "manage titles that ran out of crawl attempts",
+				Subcommands: cli.Commands{
+					{
+						Name:  "list",
+						Usage: "list dead titles",
+						Action: func(c *cli.Context) error {
+							return deadSvc.List(c.Context, os.Stdout)
+						},
+					},
+					{
+						Name:  "requeue",
+						Usage: "move dead titles back to pending",
+						Flags: []cli.Flag{idsFlag},
+						Action: func(c *cli.Context) error {
+							ids, err := parseIDs(c.StringSlice(idsFlag.Name))
+							if err != nil {
+								return err
+							}
+
+							n, err := deadSvc.Requeue(c.Context, ids)
+							if err != nil {
+								return err
+							}
+							fmt.Printf("requeued %d titles\n", n)
+							return nil
+						},
+					},
+					{
+						Name:  "purge",
+						Usage: "give up dead titles for good, their sources won't bring them back",
+						Flags: []cli.Flag{idsFlag},
+						Action: func(c *cli.Context) error {
+							ids, err := parseIDs(c.StringSlice(idsFlag.Name))
+							if err != nil {
+								return err
+							}
+							return deadSvc.Purge(c.Context, ids)
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -43,3 +141,22 @@ func Run(args []string) bool {
 
 	return true
 }
+
+func parseIDs(raw []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, r := range raw {
+		id, err := uuid.Parse(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid title id: %s", r)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return errors.Wrap(enc.Encode(v), "failed to print results")
+}
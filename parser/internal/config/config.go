@@ -14,40 +14,85 @@ type Config interface {
 	commoncfg.Config
 	Crawler
 	ServiceProvider
+	Extractors
+	UrlCrawler
+	Connector
+	Retry
+	Stories
+	Webhooks
+	Languages
+	Normalizer
 }
 
 type config struct {
 	commoncfg.Config
 	Crawler
 	ServiceProvider
+	Extractors
+	UrlCrawler
+	Connector
+	Retry
+	Stories
+	Webhooks
+	Languages
+	Normalizer
 }
 
 type yamlConfig struct {
 	LogLevel         string                       `yaml:"log_level"`
 	RateLimit        int                          `yaml:"rate_limit"`
 	CrawlEvery       time.Duration                `yaml:"crawl_every"`
+	Crawlers         []CrawlerConfig              `yaml:"crawlers"`
 	Database         commoncfg.YamlDatabaseConfig `yaml:"database"`
 	KVStore          commoncfg.YamlKVStoreConfig  `yaml:"kv_store"`
 	ServiceProviders yamlServiceProviderConfig    `yaml:"service_providers"`
+	Extractors       []ExtractorRule              `yaml:"extractors"`
+	UrlCrawler       yamlUrlCrawlerConfig         `yaml:"url_crawler"`
+	Connector        yamlConnectorConfig          `yaml:"connector"`
+	Retry            yamlRetryConfig              `yaml:"retry"`
+	Stories          yamlStoriesConfig            `yaml:"stories"`
+	Webhooks         yamlWebhooksConfig           `yaml:"webhooks"`
+	Languages        yamlLanguagesConfig          `yaml:"languages"`
+	Normalizer       yamlNormalizerConfig         `yaml:"normalizer"`
 	Runtime          commoncfg.YamlRuntimeConfig  `yaml:"runtime"`
 }
 
 func New(path string) Config {
-	cfg := yamlConfig{}
-
-	yamlConfig, err := os.ReadFile(path)
+	rawConfig, err := os.ReadFile(path)
 	if err != nil {
 		panic(errors.Wrapf(err, "failed to read config %s", path))
 	}
 
-	err = yaml.Unmarshal(yamlConfig, &cfg)
-	if err != nil {
+	cfg := parse(rawConfig, path)
+	return build(cfg, commoncfg.New(cfg.LogLevel, cfg.Runtime, cfg.Database, cfg.KVStore))
+}
+
+// NewWithCommon builds parser config on top of the given common config, e.g. the one without database in tests
+func NewWithCommon(rawConfig []byte, common commoncfg.Config) Config {
+	return build(parse(rawConfig, "<raw>"), common)
+}
+
+func parse(rawConfig []byte, path string) yamlConfig {
+	cfg := yamlConfig{}
+
+	if err := yaml.Unmarshal(rawConfig, &cfg); err != nil {
 		panic(errors.Wrapf(err, "failed to unmarshal config %s", path))
 	}
+	return cfg
+}
 
+func build(cfg yamlConfig, common commoncfg.Config) Config {
 	return &config{
-		Config:          commoncfg.New(cfg.LogLevel, cfg.Runtime, cfg.Database, cfg.KVStore),
-		Crawler:         NewCrawler(cfg.RateLimit, cfg.CrawlEvery),
+		Config:          common,
+		Crawler:         NewCrawler(cfg.RateLimit, cfg.CrawlEvery, cfg.Crawlers),
 		ServiceProvider: NewServiceProvider(cfg.ServiceProviders),
+		Extractors:      NewExtractors(cfg.Extractors),
+		UrlCrawler:      NewUrlCrawler(cfg.UrlCrawler),
+		Connector:       NewConnector(cfg.Connector),
+		Retry:           NewRetry(cfg.Retry),
+		Stories:         NewStories(cfg.Stories),
+		Webhooks:        NewWebhooks(cfg.Webhooks),
+		Languages:       NewLanguages(cfg.Languages),
+		Normalizer:      NewNormalizer(cfg.Normalizer),
 	}
 }
@@ -0,0 +1,78 @@
+package config
+
+import "time"
+
+const (
+	defaultUserAgent = "crypto-news-bot/1.0 (+https://github.com/StepanTita/crypto-news)"
+	defaultRobotsTTL = 24 * time.Hour
+
+	defaultValidatorsTTL = 7 * 24 * time.Hour
+	defaultBodyCacheTTL  = time.Hour
+)
+
+type Connector interface {
+	// UserAgent identifying user agent, its product token is also used to pick robots.txt group
+	UserAgent() string
+	// RobotsTTL how long fetched robots.txt is cached
+	RobotsTTL() time.Duration
+	// ValidatorsTTL how long ETag/Last-Modified of the fetched url are kept for conditional requests
+	ValidatorsTTL() time.Duration
+	// BodyCacheTTL how long the body of the fetched url is kept, to answer callers that need the body on 304
+	BodyCacheTTL() time.Duration
+}
+
+type yamlConnectorConfig struct {
+	UserAgent string        `yaml:"user_agent"`
+	RobotsTTL time.Duration `yaml:"robots_ttl"`
+
+	ValidatorsTTL time.Duration `yaml:"validators_ttl"`
+	BodyCacheTTL  time.Duration `yaml:"body_cache_ttl"`
+}
+
+type connector struct {
+	userAgent string
+	robotsTTL time.Duration
+
+	validatorsTTL time.Duration
+	bodyCacheTTL  time.Duration
+}
+
+func NewConnector(cfg yamlConnectorConfig) Connector {
+	c := &connector{
+		userAgent: cfg.UserAgent,
+		robotsTTL: cfg.RobotsTTL,
+
+		validatorsTTL: cfg.ValidatorsTTL,
+		bodyCacheTTL:  cfg.BodyCacheTTL,
+	}
+
+	if c.userAgent == "" {
+		c.userAgent = defaultUserAgent
+	}
+	if c.robotsTTL <= 0 {
+		c.robotsTTL = defaultRobotsTTL
+	}
+	if c.validatorsTTL <= 0 {
+		c.validatorsTTL = defaultValidatorsTTL
+	}
+	if c.bodyCacheTTL <= 0 {
+		c.bodyCacheTTL = defaultBodyCacheTTL
+	}
+	return c
+}
+
+func (c connector) UserAgent() string {
+	return c.userAgent
+}
+
+func (c connector) RobotsTTL() time.Duration {
+	return c.robotsTTL
+}
+
+func (c connector) ValidatorsTTL() time.Duration {
+	return c.validatorsTTL
+}
+
+func (c connector) BodyCacheTTL() time.Duration {
+	return c.bodyCacheTTL
+}
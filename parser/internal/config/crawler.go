@@ -1,22 +1,61 @@
 package config
 
-import "time"
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CrawlerConfig single titles source declared in the `crawlers` section
+type CrawlerConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Credentials key path in the service_providers section, e.g. [rss, feeds, coindesk]
+	Credentials []string      `yaml:"credentials"`
+	CrawlEvery  time.Duration `yaml:"crawl_every"`
+	// RateLimit max requests per second issued by this crawler, unlimited if 0
+	RateLimit int  `yaml:"rate_limit"`
+	Disabled  bool `yaml:"disabled"`
+}
+
+// Keys credentials key path of the crawler, defaults to its type
+func (c CrawlerConfig) Keys() []string {
+	if len(c.Credentials) == 0 {
+		return []string{c.Type}
+	}
+	return c.Credentials
+}
 
 type Crawler interface {
-	// TODO: think of making this service dependent
 	RateLimit() int
 	CrawlEvery() time.Duration
+	Crawlers() []CrawlerConfig
 }
 
 type crawler struct {
 	rateLimit  int
 	crawlEvery time.Duration
+	crawlers   []CrawlerConfig
 }
 
-func NewCrawler(rateLimit int, crawlEvery time.Duration) Crawler {
+// NewCrawler crawlers are scheduled by name, so the names must be unique
+func NewCrawler(rateLimit int, crawlEvery time.Duration, crawlers []CrawlerConfig) Crawler {
+	names := make(map[string]bool, len(crawlers))
+	for i := range crawlers {
+		if names[crawlers[i].Name] {
+			panic(errors.Errorf("duplicate crawler name: %s", crawlers[i].Name))
+		}
+		names[crawlers[i].Name] = true
+
+		if crawlers[i].CrawlEvery == 0 {
+			crawlers[i].CrawlEvery = crawlEvery
+		}
+	}
+
 	return &crawler{
 		rateLimit:  rateLimit,
 		crawlEvery: crawlEvery,
+		crawlers:   crawlers,
 	}
 }
 
@@ -27,3 +66,7 @@ func (l *crawler) CrawlEvery() time.Duration {
 func (l *crawler) RateLimit() int {
 	return l.rateLimit
 }
+
+func (l *crawler) Crawlers() []CrawlerConfig {
+	return l.crawlers
+}
@@ -0,0 +1,27 @@
+package config
+
+// ExtractorRule describes how to locate the article body on pages of the given hosts
+type ExtractorRule struct {
+	Hosts    []string `yaml:"hosts"`
+	Selector string   `yaml:"selector"`
+	// Exclude selectors of nodes dropped from the article before collecting text
+	Exclude []string `yaml:"exclude"`
+}
+
+type Extractors interface {
+	ExtractorRules() []ExtractorRule
+}
+
+type extractors struct {
+	rules []ExtractorRule
+}
+
+func NewExtractors(rules []ExtractorRule) Extractors {
+	return &extractors{
+		rules: rules,
+	}
+}
+
+func (e extractors) ExtractorRules() []ExtractorRule {
+	return e.rules
+}
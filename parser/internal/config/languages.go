@@ -0,0 +1,46 @@
+package config
+
+import "strings"
+
+type Languages interface {
+	// AcceptedLanguages ISO 639-1 codes of the articles accepted from the source, any language if empty
+	AcceptedLanguages(source string) []string
+}
+
+type yamlLanguagesConfig struct {
+	// Default accepted languages of the sources not listed in Sources
+	Default []string `yaml:"default"`
+	// Sources title source : accepted languages
+	Sources map[string][]string `yaml:"sources"`
+}
+
+type languages struct {
+	defaults []string
+	sources  map[string][]string
+}
+
+func NewLanguages(cfg yamlLanguagesConfig) Languages {
+	l := &languages{
+		defaults: normalizeLanguages(cfg.Default),
+		sources:  make(map[string][]string, len(cfg.Sources)),
+	}
+	for source, codes := range cfg.Sources {
+		l.sources[source] = normalizeLanguages(codes)
+	}
+	return l
+}
+
+func (l languages) AcceptedLanguages(source string) []string {
+	if codes, ok := l.sources[source]; ok {
+		return codes
+	}
+	return l.defaults
+}
+
+func normalizeLanguages(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	for _, c := range codes {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(c)))
+	}
+	return normalized
+}
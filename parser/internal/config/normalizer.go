@@ -0,0 +1,24 @@
+package config
+
+type Normalizer interface {
+	// BoilerplatePatterns case-insensitive regexps of paragraphs dropped from the article text, on top of the built-in ones
+	BoilerplatePatterns() []string
+}
+
+type yamlNormalizerConfig struct {
+	Boilerplate []string `yaml:"boilerplate"`
+}
+
+type normalizer struct {
+	boilerplate []string
+}
+
+func NewNormalizer(cfg yamlNormalizerConfig) Normalizer {
+	return &normalizer{
+		boilerplate: cfg.Boilerplate,
+	}
+}
+
+func (n normalizer) BoilerplatePatterns() []string {
+	return n.boilerplate
+}
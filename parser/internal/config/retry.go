@@ -0,0 +1,60 @@
+package config
+
+import "time"
+
+const (
+	defaultMaxAttempts     = 5
+	defaultRetryBackoff    = time.Minute
+	defaultMaxRetryBackoff = 6 * time.Hour
+)
+
+type Retry interface {
+	// MaxAttempts number of failed attempts after which title is considered dead
+	MaxAttempts() int
+	// RetryBackoff delay before the second attempt, doubled on every next one
+	RetryBackoff() time.Duration
+	MaxRetryBackoff() time.Duration
+}
+
+type yamlRetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	Backoff     time.Duration `yaml:"backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+}
+
+type retry struct {
+	maxAttempts     int
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+}
+
+func NewRetry(cfg yamlRetryConfig) Retry {
+	r := &retry{
+		maxAttempts:     cfg.MaxAttempts,
+		retryBackoff:    cfg.Backoff,
+		maxRetryBackoff: cfg.MaxBackoff,
+	}
+
+	if r.maxAttempts <= 0 {
+		r.maxAttempts = defaultMaxAttempts
+	}
+	if r.retryBackoff <= 0 {
+		r.retryBackoff = defaultRetryBackoff
+	}
+	if r.maxRetryBackoff < r.retryBackoff {
+		r.maxRetryBackoff = defaultMaxRetryBackoff
+	}
+	return r
+}
+
+func (r retry) MaxAttempts() int {
+	return r.maxAttempts
+}
+
+func (r retry) RetryBackoff() time.Duration {
+	return r.retryBackoff
+}
+
+func (r retry) MaxRetryBackoff() time.Duration {
+	return r.maxRetryBackoff
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 
 	"common/containers/deep_map"
@@ -8,6 +9,12 @@ import (
 
 type ServiceProvider interface {
 	Credentials(...string) string
+	// OptionalCredentials same as Credentials, but returns empty string if the path is not configured
+	OptionalCredentials(...string) string
+	// CredentialsList returns values of the list under the given path, empty if the path is not configured
+	CredentialsList(...string) []string
+	// CredentialsKeys lists nested keys under the given path, empty if the path is not configured
+	CredentialsKeys(...string) []string
 }
 
 type serviceProvider struct {
@@ -25,14 +32,53 @@ func NewServiceProvider(providersCreds yamlServiceProviderConfig) ServiceProvide
 }
 
 func (s serviceProvider) Credentials(keys ...string) string {
+	node, err := s.get(keys...)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to get credentials: %v", keys))
+	}
+	return node.GetScalar()
+}
+
+func (s serviceProvider) OptionalCredentials(keys ...string) string {
+	node, err := s.get(keys...)
+	if err != nil {
+		return ""
+	}
+	return node.GetScalar()
+}
+
+func (s serviceProvider) CredentialsList(keys ...string) []string {
+	node, err := s.get(keys...)
+	if err != nil {
+		return nil
+	}
+	return node.GetList()
+}
+
+func (s serviceProvider) CredentialsKeys(keys ...string) []string {
+	node, err := s.get(keys...)
+	if err != nil {
+		return nil
+	}
+	return node.Keys()
+}
+
+func (s serviceProvider) get(keys ...string) (*deep_map.DeepMap, error) {
 	currNode := s.providersConfig
 
 	var err error
 	for _, key := range keys {
 		currNode, err = currNode.Get(key)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 	}
-	return currNode.GetScalar()
+	return currNode, nil
+}
+
+// CredentialsPath joins credentials key path prefix with the nested keys, prefix is never modified
+func CredentialsPath(prefix []string, keys ...string) []string {
+	path := make([]string, 0, len(prefix)+len(keys))
+	path = append(path, prefix...)
+	return append(path, keys...)
 }
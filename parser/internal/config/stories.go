@@ -0,0 +1,48 @@
+package config
+
+import "time"
+
+const (
+	defaultStoryWindow      = 48 * time.Hour
+	defaultStoryMaxDistance = 6
+)
+
+type Stories interface {
+	// StoryWindow how far back stories are looked up when grouping a new title
+	StoryWindow() time.Duration
+	// StoryMaxDistance max simhash hamming distance for titles to be considered the same story
+	StoryMaxDistance() int
+}
+
+type yamlStoriesConfig struct {
+	Window      time.Duration `yaml:"window"`
+	MaxDistance int           `yaml:"max_distance"`
+}
+
+type stories struct {
+	window      time.Duration
+	maxDistance int
+}
+
+func NewStories(cfg yamlStoriesConfig) Stories {
+	s := &stories{
+		window:      cfg.Window,
+		maxDistance: cfg.MaxDistance,
+	}
+
+	if s.window <= 0 {
+		s.window = defaultStoryWindow
+	}
+	if s.maxDistance <= 0 {
+		s.maxDistance = defaultStoryMaxDistance
+	}
+	return s
+}
+
+func (s stories) StoryWindow() time.Duration {
+	return s.window
+}
+
+func (s stories) StoryMaxDistance() int {
+	return s.maxDistance
+}
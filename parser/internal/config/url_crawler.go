@@ -0,0 +1,111 @@
+package config
+
+import "time"
+
+const (
+	defaultUrlCrawlerWorkers = 4
+	defaultPerHostLimit      = 1
+	defaultHostRateLimit     = 1
+	defaultRequestTimeout    = 30 * time.Second
+	defaultTitlesBatchSize   = 100
+	defaultClaimLease        = 10 * time.Minute
+)
+
+type UrlCrawler interface {
+	// Workers max number of articles fetched concurrently
+	Workers() int
+	// PerHostLimit max number of concurrent requests to the same host
+	PerHostLimit() int
+	// HostRateLimit max requests per second to the same host
+	HostRateLimit() float64
+	RequestTimeout() time.Duration
+	// MaxRetries how many times request is retried after 429/503 with Retry-After
+	MaxRetries() int
+	// BatchSize number of pending titles claimed and processed at once
+	BatchSize() uint64
+	// ClaimLease how long claimed titles stay locked for other replicas, should exceed batch processing time
+	ClaimLease() time.Duration
+}
+
+type yamlUrlCrawlerConfig struct {
+	Workers        int           `yaml:"workers"`
+	PerHost        int           `yaml:"per_host"`
+	HostRateLimit  float64       `yaml:"host_rate_limit"`
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	MaxRetries     int           `yaml:"max_retries"`
+	BatchSize      uint64        `yaml:"batch_size"`
+	ClaimLease     time.Duration `yaml:"claim_lease"`
+}
+
+type urlCrawler struct {
+	workers        int
+	perHostLimit   int
+	hostRateLimit  float64
+	requestTimeout time.Duration
+	maxRetries     int
+	batchSize      uint64
+	claimLease     time.Duration
+}
+
+func NewUrlCrawler(cfg yamlUrlCrawlerConfig) UrlCrawler {
+	c := &urlCrawler{
+		workers:        cfg.Workers,
+		perHostLimit:   cfg.PerHost,
+		hostRateLimit:  cfg.HostRateLimit,
+		requestTimeout: cfg.RequestTimeout,
+		maxRetries:     cfg.MaxRetries,
+		batchSize:      cfg.BatchSize,
+		claimLease:     cfg.ClaimLease,
+	}
+
+	if c.workers <= 0 {
+		c.workers = defaultUrlCrawlerWorkers
+	}
+	if c.perHostLimit <= 0 {
+		c.perHostLimit = defaultPerHostLimit
+	}
+	if c.hostRateLimit <= 0 {
+		c.hostRateLimit = defaultHostRateLimit
+	}
+	if c.requestTimeout <= 0 {
+		c.requestTimeout = defaultRequestTimeout
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.batchSize == 0 {
+		c.batchSize = defaultTitlesBatchSize
+	}
+	if c.claimLease <= 0 {
+		c.claimLease = defaultClaimLease
+	}
+	return c
+}
+
+func (c urlCrawler) Workers() int {
+	return c.workers
+}
+
+func (c urlCrawler) PerHostLimit() int {
+	return c.perHostLimit
+}
+
+func (c urlCrawler) HostRateLimit() float64 {
+	return c.hostRateLimit
+}
+
+func (c urlCrawler) RequestTimeout() time.Duration {
+	return c.requestTimeout
+}
+
+func (c urlCrawler) MaxRetries() int {
+	return c.maxRetries
+}
+
+func (c urlCrawler) BatchSize() uint64 {
+	return c.batchSize
+}
+
+func (c urlCrawler) ClaimLease() time.Duration {
+	return c.claimLease
+}
@@ -0,0 +1,45 @@
+package config
+
+import "time"
+
+const defaultCallbackTimeout = 10 * time.Minute
+
+type Webhooks interface {
+	// WebhookAddr address the webhooks server listens on, webhooks are disabled if empty
+	WebhookAddr() string
+	// CallbackTimeout how long a task waits for its webhook before it is polled instead
+	CallbackTimeout() time.Duration
+}
+
+type yamlWebhooksConfig struct {
+	Addr            string        `yaml:"addr"`
+	CallbackTimeout time.Duration `yaml:"callback_timeout"`
+}
+
+type webhooks struct {
+	addr            string
+	callbackTimeout time.Duration
+}
+
+func NewWebhooks(cfg yamlWebhooksConfig) Webhooks {
+	w := &webhooks{
+		addr:            cfg.Addr,
+		callbackTimeout: cfg.CallbackTimeout,
+	}
+
+	// without webhooks pending tasks are polled on every crawl
+	if w.addr == "" {
+		w.callbackTimeout = 0
+	} else if w.callbackTimeout <= 0 {
+		w.callbackTimeout = defaultCallbackTimeout
+	}
+	return w
+}
+
+func (w webhooks) WebhookAddr() string {
+	return w.addr
+}
+
+func (w webhooks) CallbackTimeout() time.Duration {
+	return w.callbackTimeout
+}
@@ -1,6 +1,7 @@
 package browse_ai_crawler
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -24,57 +25,86 @@ const (
 	TaskStatusFailed     = "failed"
 )
 
-const coinTelegraphTimeLayout = "Jan 02, 2006"
-
 type rawBody struct {
 	Status  int32  `json:"statusCode"`
 	Message string `json:"messageCode"`
-	Result  struct {
-		Id                string  `json:"id"`
-		RobotId           string  `json:"robotId"`
-		Status            string  `json:"status"`
-		CreatedAt         *int64  `json:"createdAt"`
-		StartedAt         *int64  `json:"startedAt"`
-		FinishedAt        *int64  `json:"finishedAt"`
-		UserFriendlyError *string `json:"userFriendlyError"`
-		CapturedLists     *struct {
-			Releases []body `json:"coin_telegraph_posts"`
-		} `json:"capturedLists"`
-	} `json:"result"`
+	Result  task   `json:"result"`
+}
+
+type task struct {
+	Id                string  `json:"id"`
+	RobotId           string  `json:"robotId"`
+	Status            string  `json:"status"`
+	CreatedAt         *int64  `json:"createdAt"`
+	StartedAt         *int64  `json:"startedAt"`
+	FinishedAt        *int64  `json:"finishedAt"`
+	UserFriendlyError *string `json:"userFriendlyError"`
+	// list name : captured rows, columns are named by the robot author
+	CapturedLists map[string][]map[string]any `json:"capturedLists"`
 }
 
 type body struct {
-	ReleaseDate  string `json:"release_date"`
-	ReleaseURL   string `json:"release_url"`
-	ReleaseTitle string `json:"release_title"`
+	title       string
+	url         string
+	summary     string
+	releaseDate string
+
+	robot *robot
 }
 
 func (b body) ToModel() any {
+	var summary *string
+	if b.summary != "" {
+		summary = &b.summary
+	}
+
 	return model.Title{
-		Title:       &b.ReleaseTitle,
-		Hash:        convert.ToPtr(hash.Hash(b.ReleaseTitle, b.ReleaseURL)),
-		URL:         &b.ReleaseURL,
-		ReleaseDate: processReleaseDate(b.ReleaseDate),
+		Title:       &b.title,
+		Summary:     summary,
+		Hash:        convert.ToPtr(hash.Hash(b.title, b.url)),
+		URL:         &b.url,
+		ReleaseDate: processReleaseDate(b.releaseDate, b.robot.dateLayouts),
 		Status:      convert.ToPtr(model.StatusPending),
-		Source:      convert.ToPtr("cointelegraph"),
+		Source:      convert.ToPtr(b.robot.source),
+		Kind:        convert.ToPtr(model.KindNews),
 	}
 }
 
-func processReleaseDate(s string) *time.Time {
-	t, err := time.Parse(coinTelegraphTimeLayout, s)
-	if err != nil {
-		perr := err.(*time.ParseError)
-		logrus.WithError(perr).Debug("Parsing failed...")
+// field captured values are mostly strings, but numeric columns (e.g. Position) are decoded as numbers
+func field(row map[string]any, name string) string {
+	if name == "" {
+		return ""
+	}
+	switch v := row[name].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
 
-		tu, err := matchTimePassed(s)
-		if err != nil {
-			logrus.WithError(err).Debug("Match time parsing failed...")
-			return nil
+func processReleaseDate(s string, layouts []string) *time.Time {
+	if s == "" {
+		return nil
+	}
+
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return convert.ToPtr(t.UTC())
 		}
-		return convert.ToPtr(common.CurrentTimestamp().Add(-tu).Truncate(tu))
+		logrus.WithError(err).Debug("Parsing failed...")
 	}
 
-	return &t
+	tu, err := matchTimePassed(s)
+	if err != nil {
+		logrus.WithError(err).Debug("Match time parsing failed...")
+		return nil
+	}
+	// relative dates are estimates only, precise time is resolved from the article page later
+	return convert.ToPtr(common.CurrentTimestamp().Add(-tu))
 }
 
 func convertTimeUnit(unit string) (time.Duration, error) {
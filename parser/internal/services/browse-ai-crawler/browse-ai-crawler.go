@@ -4,15 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"common/convert"
 	"common/data/store"
 	"common/iteration"
 	"parser/internal/config"
@@ -27,30 +26,88 @@ type BrowseAICrawler struct {
 
 	authToken string
 	url       string
-	robotID   string
+	robot     robot
 	conn      connector.Connector
+	tasks     *tasks
+
+	callbackTimeout time.Duration
 
 	dataProvider store.DataProvider
 }
 
-func NewCrawler(cfg config.Config, robotID string) crawler.Crawler {
+// NewCrawler robot keys point to the robot definition, e.g. [browse_ai, robots, coin_telegraph]
+func NewCrawler(cfg config.Config, conn connector.Connector, robotKeys ...string) crawler.Crawler {
+	dataProvider := store.New(cfg)
+	rbt := newRobot(cfg, robotKeys...)
 	return &BrowseAICrawler{
 		log: cfg.Logging().WithField("service", "[BROWSE-AI-CRAWLER]"),
 
 		authToken: cfg.Credentials(BrowseAI, "auth_token"),
 		url:       cfg.Credentials(BrowseAI, "url"),
-		robotID:   robotID,
+		robot:     rbt,
+		tasks:     newTasks(dataProvider.KVProvider(), rbt.id),
+
+		callbackTimeout: cfg.CallbackTimeout(),
 
-		dataProvider: store.New(cfg),
+		dataProvider: dataProvider,
 
-		conn: connector.New(cfg),
+		conn: conn,
 	}
 }
 
+// Crawl starts a new robot task, its results are delivered by the webhook,
+// tasks that have not called back in time are polled on the next crawls
 func (c BrowseAICrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	if err := c.tasks.load(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to load pending tasks")
+	}
+
+	bodies, err := c.pollPending(ctx)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to poll pending tasks")
+	}
+
+	// robot is still busy with the previous task, no reason to queue another one
+	if c.tasks.size() > 0 {
+		c.log.WithField("robot-id", c.robot.id).Debug("Previous task is still pending, skipping...")
+		return bodies, http.StatusOK, nil
+	}
+
+	created, statusCode, err := c.createTask(ctx)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to create task")
+	}
+
+	if statusCode != http.StatusOK {
+		// results of the pending tasks are claimed already and would be lost otherwise
+		if len(bodies) > 0 {
+			c.log.WithField("status-code", statusCode).Warn("failed to create task, returning pending tasks results...")
+			return bodies, http.StatusOK, nil
+		}
+		return nil, statusCode, nil
+	}
+
+	switch created.Status {
+	case TaskStatusSuccessful:
+		bodies = append(bodies, c.taskBodies(created)...)
+	case TaskStatusFailed:
+		c.log.WithField("error", convert.FromPtr(created.UserFriendlyError)).Warn("task run failed...")
+	default:
+		if err := c.tasks.add(ctx, pendingTask{
+			ID:        created.Id,
+			RobotID:   c.robot.id,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return nil, 0, err
+		}
+	}
+	return bodies, http.StatusOK, nil
+}
+
+func (c BrowseAICrawler) createTask(ctx context.Context) (*task, int, error) {
 	taskBody, statusCode, err := c.conn.Post(ctx, connector.RequestParams{
 		Url:  c.url,
-		Path: fmt.Sprintf("/v2/robots/%s/tasks", c.robotID),
+		Path: fmt.Sprintf("/v2/robots/%s/tasks", c.robot.id),
 		Body: nil,
 		Headers: http.Header{
 			"Authorization": []string{fmt.Sprintf("Bearer %s", c.authToken)},
@@ -65,52 +122,95 @@ func (c BrowseAICrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int,
 	if err := json.NewDecoder(taskBody).Decode(&taskRespBody); err != nil {
 		return nil, statusCode, errors.Wrap(err, "failed to decode create task response body")
 	}
+	return &taskRespBody.Result, statusCode, nil
+}
 
-	if statusCode != http.StatusOK {
-		return nil, statusCode, nil
-	}
+// pollPending forgets tasks delivered by the webhook and polls the ones that have not called back in time,
+// each of them is polled once per crawl
+func (c BrowseAICrawler) pollPending(ctx context.Context) ([]crawler.ParsedBody, error) {
+	bodies := make([]crawler.ParsedBody, 0)
+	for _, p := range c.tasks.list() {
+		if c.tasks.delivered(ctx, p.ID) {
+			c.tasks.drop(p.ID)
+			continue
+		}
 
-	var rawPollBody io.Reader
-	pollRespBody := taskRespBody
-	for statusCode == http.StatusOK && pollRespBody.Result.Status == TaskStatusInProgress {
-		rawPollBody, statusCode, err = c.conn.Poll(ctx, connector.PollParams{
-			Url:  c.url,
-			Path: fmt.Sprintf("/v2/robots/%s/tasks/%s", c.robotID, taskRespBody.Result.Id),
-			Headers: http.Header{
-				"Authorization": []string{fmt.Sprintf("Bearer %s", c.authToken)},
-			},
-			Params: url.Values{
-				"coin_telegraph_posts_limit": []string{"10"},
-			},
-		})
+		if time.Since(p.CreatedAt) < c.callbackTimeout {
+			continue
+		}
 
+		polled, statusCode, err := c.pollTask(ctx, p.ID)
 		if err != nil {
-			return nil, 0, errors.Wrap(err, "failed to poll browse-ai API")
+			return nil, err
+		}
+		if statusCode != http.StatusOK {
+			c.log.WithFields(logrus.Fields{
+				"task-id":     p.ID,
+				"status-code": statusCode,
+			}).Warn("failed to poll task...")
+			continue
 		}
 
-		if err := json.NewDecoder(rawPollBody).Decode(&pollRespBody); err != nil {
-			return nil, statusCode, errors.Wrap(err, "failed to decode response body")
+		if polled.Status == TaskStatusInProgress && time.Since(p.CreatedAt) < maxTaskAge {
+			continue
 		}
 
-		if pollRespBody.Result.Status == TaskStatusInProgress {
-			time.Sleep(15 * time.Second)
+		claimed, err := claimTask(ctx, c.dataProvider.KVProvider(), p.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.tasks.drop(p.ID)
+
+		switch {
+		case !claimed:
+			continue
+		case polled.Status == TaskStatusSuccessful:
+			bodies = append(bodies, c.taskBodies(polled)...)
+
+			// task is given back if its titles are not stored, so that the next poll delivers it
+			crawler.AfterStore(ctx, nil, func(ctx context.Context) {
+				if err := c.tasks.add(ctx, p); err != nil {
+					c.log.WithError(err).WithField("task-id", p.ID).Error("failed to restore pending task")
+				}
+			})
+		case polled.Status == TaskStatusFailed:
+			c.log.WithField("error", convert.FromPtr(polled.UserFriendlyError)).Warn("task run failed...")
+		default:
+			c.log.WithField("task-id", p.ID).Warn("task has not finished in time, giving up...")
 		}
 	}
+	return bodies, nil
+}
 
-	// TODO: temporary workaround to make sure we only parse /news
-	pollRespBody.Result.CapturedLists.Releases = iteration.Filter(pollRespBody.Result.CapturedLists.Releases, func(b body) bool {
-		return strings.HasPrefix(b.ReleaseURL, "https://cointelegraph.com/news/")
+func (c BrowseAICrawler) pollTask(ctx context.Context, id string) (*task, int, error) {
+	rawPollBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:  c.url,
+		Path: fmt.Sprintf("/v2/robots/%s/tasks/%s", c.robot.id, id),
+		Headers: http.Header{
+			"Authorization": []string{fmt.Sprintf("Bearer %s", c.authToken)},
+		},
+		Params: url.Values{
+			fmt.Sprintf("%s_limit", c.robot.capturedList): []string{c.robot.listLimit},
+		},
+		SkipRobots: true,
 	})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to poll browse-ai API")
+	}
 
 	if statusCode != http.StatusOK {
 		return nil, statusCode, nil
 	}
 
-	if pollRespBody.Result.Status == TaskStatusFailed {
-		return nil, statusCode, errors.Wrapf(errors.New("task run failed"), "%v", pollRespBody.Result.UserFriendlyError)
+	var pollRespBody rawBody
+	if err := json.NewDecoder(rawPollBody).Decode(&pollRespBody); err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to decode response body")
 	}
+	return &pollRespBody.Result, statusCode, nil
+}
 
-	return iteration.Map(pollRespBody.Result.CapturedLists.Releases, toModel), statusCode, nil
+func (c BrowseAICrawler) taskBodies(t *task) []crawler.ParsedBody {
+	return iteration.Map(c.robot.bodies(t.CapturedLists), toModel)
 }
 
 func toModel(b body) crawler.ParsedBody {
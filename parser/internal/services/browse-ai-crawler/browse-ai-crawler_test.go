@@ -0,0 +1,179 @@
+package browse_ai_crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"common"
+	"common/data/model"
+	"parser/internal/config"
+	"parser/internal/services/crawler"
+	"parser/internal/testutil"
+)
+
+const testConfig = `
+crawlers:
+  - name: coin_telegraph
+    type: browse_ai
+    credentials: [ browse_ai, robots, coin_telegraph ]
+service_providers:
+  services:
+    browse_ai:
+      auth_token: token
+      webhook_secret: secret
+      url: https://api.browse.ai
+      robots:
+        coin_telegraph:
+          robot_id: robot-1
+          captured_list: coin_telegraph_posts
+          list_limit: 10
+          source: cointelegraph
+          fields:
+            title: release_title
+            url: release_url
+            release_date: release_date
+          url_patterns: [ '^https://cointelegraph\.com/news/' ]
+          date_layouts: [ "Jan 02, 2006" ]
+`
+
+func titles(t *testing.T, bodies []crawler.ParsedBody) []model.Title {
+	t.Helper()
+
+	res := make([]model.Title, 0, len(bodies))
+	for _, b := range bodies {
+		title, ok := b.ToModel().(model.Title)
+		require.True(t, ok, "expected title model, got %T", b.ToModel())
+		res = append(res, title)
+	}
+	return res
+}
+
+func newCrawler(t *testing.T) (crawler.Crawler, config.Config) {
+	t.Helper()
+
+	return testutil.Crawler(t, testConfig, "browse-ai", NewCrawler, "browse_ai", "robots", "coin_telegraph")
+}
+
+func TestCrawlPollsPendingTask(t *testing.T) {
+	c, _ := newCrawler(t)
+
+	// the first crawl only creates the task, its results are not there yet
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Empty(t, bodies, "expected no titles before the task is finished")
+
+	// webhooks are disabled, so the pending task is polled on the next crawl
+	bodies, statusCode, err = c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	got := titles(t, bodies)
+	require.Len(t, got, 2, "expected rows without title or with not allowed urls to be dropped")
+
+	first := got[0]
+	require.Equal(t, "Bitcoin ETF decision delayed again", *first.Title)
+	require.Equal(t, "cointelegraph", *first.Source)
+	require.NotNil(t, first.ReleaseDate)
+	require.True(t, first.ReleaseDate.Equal(time.Date(2023, time.October, 17, 0, 0, 0, 0, time.UTC)),
+		"unexpected release date %s", first.ReleaseDate)
+
+	second := got[1]
+	require.True(t, strings.HasPrefix(*second.URL, "https://cointelegraph.com/news/"), "unexpected url: %s", *second.URL)
+	require.NotNil(t, second.ReleaseDate)
+	require.Equal(t, 3*time.Hour, common.CurrentTimestamp().Sub(*second.ReleaseDate).Round(time.Hour),
+		"expected relative release date 3 hours ago")
+}
+
+func TestCrawlResumesPendingTaskAfterRestart(t *testing.T) {
+	c, cfg := newCrawler(t)
+
+	// registers task-1 as pending and goes down
+	_, _, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+
+	c = NewCrawler(cfg, testutil.Connector(cfg, "browse-ai"), "browse_ai", "robots", "coin_telegraph")
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, bodies, 2, "expected the task pending before the restart to be polled")
+}
+
+func TestCrawlRestoresPolledTaskIfNotStored(t *testing.T) {
+	c, _ := newCrawler(t)
+
+	// registers task-1 as pending
+	_, _, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+
+	crawlCtx, deferred := crawler.Defer(context.Background())
+	bodies, _, err := c.Crawl(crawlCtx)
+	require.NoError(t, err)
+	require.Len(t, bodies, 2)
+
+	// titles failed to be stored, so the task is polled again
+	deferred.Rollback(context.Background())
+	bodies, _, err = c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Len(t, bodies, 2, "expected the task to be delivered again")
+}
+
+func TestWebhookRequiresSecret(t *testing.T) {
+	cfg := testutil.Config(t, strings.Replace(testConfig, "webhook_secret: secret", "webhook_secret: ''", 1))
+	_, err := NewWebhookHandler(cfg, nil)
+	require.Error(t, err, "expected webhook handler without the secret to be refused")
+}
+
+func TestWebhookDeliversTaskOnce(t *testing.T) {
+	c, cfg := newCrawler(t)
+
+	// registers task-1 as pending
+	_, _, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+
+	var received []crawler.ParsedBody
+	handler, err := NewWebhookHandler(cfg, func(_ context.Context, bodies []crawler.ParsedBody) error {
+		received = append(received, bodies...)
+		return nil
+	})
+	require.NoError(t, err)
+
+	payload, err := os.ReadFile(filepath.Join("testdata", "webhook-task-finished.json"))
+	require.NoError(t, err)
+
+	deliver := func(target string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(string(payload))))
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, deliver("/webhooks/browse-ai?secret=wrong"), "expected wrong secret to be rejected")
+	require.Equal(t, http.StatusNoContent, deliver("/webhooks/browse-ai?secret=secret"))
+	require.Len(t, received, 2)
+
+	// replayed webhook is acknowledged and skipped
+	require.Equal(t, http.StatusNoContent, deliver("/webhooks/browse-ai?secret=secret"))
+	require.Len(t, received, 2, "expected replayed webhook to be skipped")
+}
+
+func TestProcessReleaseDate(t *testing.T) {
+	layouts := []string{"Jan 02, 2006", time.RFC3339}
+
+	got := processReleaseDate("2023-10-17T08:30:00+02:00", layouts)
+	require.NotNil(t, got)
+	require.True(t, got.Equal(time.Date(2023, 10, 17, 6, 30, 0, 0, time.UTC)), "expected RFC3339 date to be parsed into UTC, got %s", got)
+
+	got = processReleaseDate("45 MINUTES AGO", layouts)
+	require.NotNil(t, got)
+	require.Equal(t, 45*time.Minute, common.CurrentTimestamp().Sub(*got).Round(time.Minute))
+
+	require.Nil(t, processReleaseDate("yesterday", layouts), "expected unknown date format to be skipped")
+}
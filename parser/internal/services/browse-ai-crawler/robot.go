@@ -0,0 +1,100 @@
+package browse_ai_crawler
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"parser/internal/config"
+)
+
+const defaultListLimit = "10"
+
+// robot data-driven definition of the Browse AI robot and the way its captured list maps into titles
+type robot struct {
+	id           string
+	capturedList string
+	listLimit    string
+	source       string
+
+	fields fieldsMapping
+
+	urlPatterns []*regexp.Regexp
+	dateLayouts []string
+}
+
+// fieldsMapping names of the captured list columns for each of the title fields
+type fieldsMapping struct {
+	title       string
+	url         string
+	summary     string
+	releaseDate string
+}
+
+func newRobot(cfg config.Config, robotKeys ...string) robot {
+	listLimit := cfg.OptionalCredentials(config.CredentialsPath(robotKeys, "list_limit")...)
+	if listLimit == "" {
+		listLimit = defaultListLimit
+	}
+
+	rawPatterns := cfg.CredentialsList(config.CredentialsPath(robotKeys, "url_patterns")...)
+	urlPatterns := make([]*regexp.Regexp, len(rawPatterns))
+	for i, p := range rawPatterns {
+		r, err := regexp.Compile(p)
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to compile url pattern: %s", p))
+		}
+		urlPatterns[i] = r
+	}
+
+	return robot{
+		id:           cfg.Credentials(config.CredentialsPath(robotKeys, "robot_id")...),
+		capturedList: cfg.Credentials(config.CredentialsPath(robotKeys, "captured_list")...),
+		listLimit:    listLimit,
+		source:       cfg.Credentials(config.CredentialsPath(robotKeys, "source")...),
+
+		fields: fieldsMapping{
+			title:       cfg.Credentials(config.CredentialsPath(robotKeys, "fields", "title")...),
+			url:         cfg.Credentials(config.CredentialsPath(robotKeys, "fields", "url")...),
+			summary:     cfg.OptionalCredentials(config.CredentialsPath(robotKeys, "fields", "summary")...),
+			releaseDate: cfg.OptionalCredentials(config.CredentialsPath(robotKeys, "fields", "release_date")...),
+		},
+
+		urlPatterns: urlPatterns,
+		dateLayouts: cfg.CredentialsList(config.CredentialsPath(robotKeys, "date_layouts")...),
+	}
+}
+
+// allowed url is allowed if it matches any of the patterns, everything is allowed if there are no patterns
+func (r robot) allowed(url string) bool {
+	if len(r.urlPatterns) == 0 {
+		return true
+	}
+	for _, p := range r.urlPatterns {
+		if p.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodies maps captured list rows into the parsed bodies, dropping rows with not allowed urls
+func (r robot) bodies(capturedLists map[string][]map[string]any) []body {
+	rows := capturedLists[r.capturedList]
+
+	bodies := make([]body, 0, len(rows))
+	for _, row := range rows {
+		b := body{
+			title:       field(row, r.fields.title),
+			url:         field(row, r.fields.url),
+			summary:     field(row, r.fields.summary),
+			releaseDate: field(row, r.fields.releaseDate),
+			robot:       &r,
+		}
+		if b.title == "" || b.url == "" || !r.allowed(b.url) {
+			continue
+		}
+		bodies = append(bodies, b)
+	}
+	return bodies
+}
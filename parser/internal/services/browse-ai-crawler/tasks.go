@@ -0,0 +1,121 @@
+package browse_ai_crawler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"common/data"
+	"common/data/queriers"
+)
+
+// maxTaskAge tasks that have neither called back nor finished by then are given up
+const maxTaskAge = 6 * time.Hour
+
+// pendingTask task created by us and not delivered yet, it is kept in KV,
+// so the webhook received by any of the replicas can claim it
+type pendingTask struct {
+	ID        string    `json:"id"`
+	RobotID   string    `json:"robot_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func pendingTaskKey(id string) string {
+	return "browse-ai/tasks/" + id
+}
+
+// claimTask removes pending task, false means it is unknown or was already delivered,
+// the removal is atomic, so the webhook and the poll never both deliver the task
+func claimTask(ctx context.Context, kv queriers.KVProvider, id string) (bool, error) {
+	claimed, err := kv.Take(ctx, pendingTaskKey(id))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to claim pending task")
+	}
+	return claimed, nil
+}
+
+// tasks created by the crawler, they are polled if webhook does not arrive in time
+type tasks struct {
+	kv      queriers.KVProvider
+	robotID string
+
+	mu      sync.Mutex
+	loaded  bool
+	pending []pendingTask
+}
+
+func newTasks(kv queriers.KVProvider, robotID string) *tasks {
+	return &tasks{kv: kv, robotID: robotID}
+}
+
+// load picks up the tasks of the robot left pending before the restart, only once
+func (t *tasks) load(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.loaded {
+		return nil
+	}
+
+	keys, err := t.kv.Keys(ctx, pendingTaskKey(""))
+	if err != nil {
+		return errors.Wrap(err, "failed to list pending tasks")
+	}
+
+	for _, key := range keys {
+		var p pendingTask
+		if err := t.kv.GetStruct(ctx, key, &p); err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				continue
+			}
+			return errors.Wrap(err, "failed to get pending task")
+		}
+		if p.RobotID == t.robotID {
+			t.pending = append(t.pending, p)
+		}
+	}
+
+	t.loaded = true
+	return nil
+}
+
+func (t *tasks) add(ctx context.Context, p pendingTask) error {
+	if _, err := t.kv.SetStruct(ctx, pendingTaskKey(p.ID), p, maxTaskAge); err != nil {
+		return errors.Wrap(err, "failed to store pending task")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, p)
+	return nil
+}
+
+// delivered task is no longer tracked, either the webhook or the poll has handled it
+func (t *tasks) delivered(ctx context.Context, id string) bool {
+	var p pendingTask
+	return errors.Is(t.kv.GetStruct(ctx, pendingTaskKey(id), &p), data.ErrNotFound)
+}
+
+func (t *tasks) drop(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, p := range t.pending {
+		if p.ID == id {
+			t.pending = append(t.pending[:i], t.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *tasks) size() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+func (t *tasks) list() []pendingTask {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]pendingTask(nil), t.pending...)
+}
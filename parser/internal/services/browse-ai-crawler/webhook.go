@@ -0,0 +1,153 @@
+package browse_ai_crawler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common/convert"
+	"common/data/queriers"
+	"common/data/store"
+	"common/iteration"
+	"parser/internal/config"
+	"parser/internal/services/crawler"
+)
+
+const (
+	// maxWebhookBody captured lists are limited by list_limit, so payloads are small
+	maxWebhookBody = 10 * 1024 * 1024
+
+	HeaderWebhookSecret = "X-Webhook-Secret"
+	QueryWebhookSecret  = "secret"
+)
+
+// Sink stores titles received outside the crawl loop
+type Sink func(ctx context.Context, bodies []crawler.ParsedBody) error
+
+// webhookPayload task-finished webhook, task is the same object the tasks API returns
+type webhookPayload struct {
+	Event string `json:"event"`
+	Task  task   `json:"task"`
+}
+
+type webhookHandler struct {
+	log *logrus.Entry
+
+	secret string
+	// robot id : robot
+	robots map[string]robot
+	kv     queriers.KVProvider
+	sink   Sink
+}
+
+// NewWebhookHandler accepts webhooks of the tasks created by configured browse_ai crawlers,
+// the shared secret is expected in the X-Webhook-Secret header or the secret query param of the webhook url
+func NewWebhookHandler(cfg config.Config, sink Sink) (http.Handler, error) {
+	// anyone could deliver titles otherwise
+	secret := cfg.OptionalCredentials(BrowseAI, "webhook_secret")
+	if secret == "" {
+		return nil, errors.New("browse_ai webhook_secret is not set")
+	}
+
+	robots := make(map[string]robot)
+	for _, c := range cfg.Crawlers() {
+		if c.Type != BrowseAI || c.Disabled {
+			continue
+		}
+		r := newRobot(cfg, c.Keys()...)
+		robots[r.id] = r
+	}
+
+	return &webhookHandler{
+		log: cfg.Logging().WithField("service", "[BROWSE-AI-WEBHOOK]"),
+
+		secret: secret,
+		robots: robots,
+		kv:     store.New(cfg).KVProvider(),
+		sink:   sink,
+	}, nil
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.verify(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload webhookPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody)).Decode(&payload); err != nil {
+		h.log.WithError(err).Warn("failed to decode webhook payload")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log := h.log.WithFields(logrus.Fields{
+		"event":   payload.Event,
+		"task-id": payload.Task.Id,
+	})
+
+	rbt, ok := h.robots[payload.Task.RobotId]
+	if !ok {
+		log.WithField("robot-id", payload.Task.RobotId).Warn("webhook of unknown robot")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// not finished yet, e.g. task-started events
+	if payload.Task.Status == TaskStatusInProgress {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// only tasks created by us are accepted, each of them once, so replayed webhooks are ignored
+	claimed, err := claimTask(r.Context(), h.kv, payload.Task.Id)
+	if err != nil {
+		log.WithError(err).Error("failed to claim task")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !claimed {
+		log.Debug("task is unknown or already delivered, skipping...")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if payload.Task.Status == TaskStatusFailed {
+		log.WithField("error", convert.FromPtr(payload.Task.UserFriendlyError)).Warn("task run failed...")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	bodies := iteration.Map(rbt.bodies(payload.Task.CapturedLists), toModel)
+	if err := h.sink(r.Context(), bodies); err != nil {
+		log.WithError(err).Error("failed to store webhook titles")
+
+		// task is given back, so that the retried webhook or the poll can deliver it
+		if _, err := h.kv.SetStruct(r.Context(), pendingTaskKey(payload.Task.Id), pendingTask{
+			ID:        payload.Task.Id,
+			RobotID:   rbt.id,
+			CreatedAt: time.Now(),
+		}, maxTaskAge); err != nil {
+			log.WithError(err).Error("failed to restore pending task")
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Debugf("Received %d titles from webhook", len(bodies))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *webhookHandler) verify(r *http.Request) bool {
+	secret := r.Header.Get(HeaderWebhookSecret)
+	if secret == "" {
+		secret = r.URL.Query().Get(QueryWebhookSecret)
+	}
+	return h.secret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(h.secret)) == 1
+}
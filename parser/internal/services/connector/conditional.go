@@ -0,0 +1,125 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common/data"
+	"common/data/queriers"
+	"parser/internal/services/crawler"
+)
+
+// maxCachedBody larger bodies keep only validators
+const maxCachedBody = 1024 * 1024
+
+// cachedResponse validators of the last successful response and its body, while it is fresh
+type cachedResponse struct {
+	ETag          string    `json:"etag,omitempty"`
+	LastModified  string    `json:"last_modified,omitempty"`
+	Body          []byte    `json:"body,omitempty"`
+	BodyExpiresAt time.Time `json:"body_expires_at"`
+}
+
+func (c cachedResponse) hasBody(now time.Time) bool {
+	return c.Body != nil && now.Before(c.BodyExpiresAt)
+}
+
+type httpCache struct {
+	log *logrus.Entry
+
+	kv            queriers.KVProvider
+	validatorsTTL time.Duration
+	bodyTTL       time.Duration
+}
+
+func newHTTPCache(log *logrus.Entry, kv queriers.KVProvider, validatorsTTL, bodyTTL time.Duration) *httpCache {
+	return &httpCache{
+		log:           log,
+		kv:            kv,
+		validatorsTTL: validatorsTTL,
+		bodyTTL:       bodyTTL,
+	}
+}
+
+func cacheKey(reqURL string) string {
+	return "http-cache/" + reqURL
+}
+
+// get cache is an optimization only, failures are logged and treated as a miss, so is the disabled cache
+func (c *httpCache) get(ctx context.Context, reqURL string) *cachedResponse {
+	if c == nil {
+		return nil
+	}
+
+	var cached cachedResponse
+	if err := c.kv.GetStruct(ctx, cacheKey(reqURL), &cached); err != nil {
+		if !errors.Is(err, data.ErrNotFound) {
+			c.log.WithError(err).Warn("failed to get cached response...")
+		}
+		return nil
+	}
+	return &cached
+}
+
+// store keeps validators of the response, body is read in full and returned to the caller,
+// validators are only saved once the crawl results are stored, if the crawl defers them
+func (c *httpCache) store(ctx context.Context, reqURL string, resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	cached := cachedResponse{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if c == nil || (cached.ETag == "" && cached.LastModified == "") {
+		return body, nil
+	}
+
+	if len(body) <= maxCachedBody {
+		cached.Body = body
+		cached.BodyExpiresAt = time.Now().Add(c.bodyTTL)
+	}
+
+	// a crawl that failed to store its results must not save them, otherwise the next poll gets 304
+	// and the results are never fetched again
+	if !crawler.AfterStore(ctx, func(ctx context.Context) {
+		c.save(ctx, reqURL, cached)
+	}, nil) {
+		c.save(ctx, reqURL, cached)
+	}
+	return body, nil
+}
+
+func (c *httpCache) save(ctx context.Context, reqURL string, cached cachedResponse) {
+	if _, err := c.kv.SetStruct(ctx, cacheKey(reqURL), cached, c.validatorsTTL); err != nil {
+		c.log.WithError(err).Warn("failed to cache response...")
+	}
+}
+
+// setValidators makes the request conditional on the cached response
+func (c cachedResponse) setValidators(h http.Header) {
+	if c.ETag != "" {
+		h.Set("If-None-Match", c.ETag)
+	}
+	if c.LastModified != "" {
+		h.Set("If-Modified-Since", c.LastModified)
+	}
+}
+
+func notModifiedResponse(header http.Header) *Response {
+	return &Response{
+		Body:       bytes.NewReader(nil),
+		StatusCode: http.StatusNotModified,
+		Header:     header,
+	}
+}
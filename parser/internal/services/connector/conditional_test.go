@@ -0,0 +1,144 @@
+package connector_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+	"parser/internal/testutil"
+)
+
+const (
+	feedBody     = "<rss>feed</rss>"
+	feedETag     = `"v1"`
+	lastModified = "Tue, 17 Oct 2023 08:30:00 GMT"
+)
+
+// feedServer answers 304 to the requests carrying the current validators, the headers of the requests are recorded
+type feedServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []http.Header
+}
+
+func newFeedServer(t *testing.T) *feedServer {
+	s := &feedServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Header.Clone())
+		s.mu.Unlock()
+
+		if r.Header.Get("If-None-Match") == feedETag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", feedETag)
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = io.WriteString(w, feedBody)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// lastRequest headers of the last feed request
+func (s *feedServer) lastRequest() http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func poll(t *testing.T, ctx context.Context, conn connector.Connector, url string, conditional bool) (string, int) {
+	t.Helper()
+
+	body, statusCode, err := conn.Poll(ctx, connector.PollParams{Url: url, Path: "/feed", Conditional: conditional})
+	require.NoError(t, err)
+
+	raw, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return string(raw), statusCode
+}
+
+func TestConditionalRoundTrip(t *testing.T) {
+	cfg := testutil.Config(t, "")
+	conn := connector.New(cfg)
+	srv := newFeedServer(t)
+
+	body, statusCode := poll(t, context.Background(), conn, srv.URL, true)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Equal(t, feedBody, body)
+	require.Empty(t, srv.lastRequest().Get("If-None-Match"))
+
+	// the caller handles 304 itself, so it gets no body
+	body, statusCode = poll(t, context.Background(), conn, srv.URL, true)
+	require.Equal(t, http.StatusNotModified, statusCode)
+	require.Empty(t, body)
+	require.Equal(t, feedETag, srv.lastRequest().Get("If-None-Match"))
+	require.Equal(t, lastModified, srv.lastRequest().Get("If-Modified-Since"))
+}
+
+func TestNotModifiedFromCachedBody(t *testing.T) {
+	cfg := testutil.Config(t, "")
+	conn := connector.New(cfg)
+	srv := newFeedServer(t)
+
+	_, statusCode := poll(t, context.Background(), conn, srv.URL, true)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// the caller needs the body, 304 is answered with the cached one
+	body, statusCode := poll(t, context.Background(), conn, srv.URL, false)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Equal(t, feedBody, body)
+	require.Equal(t, feedETag, srv.lastRequest().Get("If-None-Match"))
+}
+
+func TestValidatorsSavedAfterStore(t *testing.T) {
+	cfg := testutil.Config(t, "")
+	conn := connector.New(cfg)
+	srv := newFeedServer(t)
+
+	// the results of the crawl are dropped, so the next crawl must get the content again
+	crawlCtx, deferred := crawler.Defer(context.Background())
+	_, statusCode := poll(t, crawlCtx, conn, srv.URL, true)
+	require.Equal(t, http.StatusOK, statusCode)
+	deferred.Rollback(context.Background())
+
+	crawlCtx, deferred = crawler.Defer(context.Background())
+	body, statusCode := poll(t, crawlCtx, conn, srv.URL, true)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Equal(t, feedBody, body)
+	require.Empty(t, srv.lastRequest().Get("If-None-Match"))
+
+	// the results are stored, the validators are saved along
+	deferred.Commit(context.Background())
+
+	_, statusCode = poll(t, context.Background(), conn, srv.URL, true)
+	require.Equal(t, http.StatusNotModified, statusCode)
+	require.Equal(t, feedETag, srv.lastRequest().Get("If-None-Match"))
+}
+
+func TestWithoutCache(t *testing.T) {
+	cfg := testutil.Config(t, "")
+	srv := newFeedServer(t)
+
+	_, statusCode := poll(t, context.Background(), connector.New(cfg), srv.URL, true)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	// dry runs neither send the saved validators nor get 304
+	body, statusCode := poll(t, context.Background(), connector.New(cfg, connector.WithoutCache()), srv.URL, true)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Equal(t, feedBody, body)
+	require.Empty(t, srv.lastRequest().Get("If-None-Match"))
+}
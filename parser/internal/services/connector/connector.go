@@ -6,48 +6,153 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"common/data/store"
 	"parser/internal/config"
 )
 
 type Connector interface {
 	Post(ctx context.Context, r RequestParams) (io.Reader, int, error)
 	Poll(ctx context.Context, r PollParams) (io.Reader, int, error)
+	// Fetch same as Poll, but exposes response headers, e.g. Retry-After
+	Fetch(ctx context.Context, r PollParams) (*Response, error)
 }
 
 type connector struct {
 	log *logrus.Entry
 
-	client http.Client
+	client    *http.Client
+	userAgent string
+	robots    *robotsChecker
+	cache     *httpCache
 }
 
-func New(cfg config.Config) Connector {
-	return &connector{
-		log: cfg.Logging().WithField("service", "[CONN]"),
-		client: http.Client{
-			Timeout: 0,
-		},
+// Option customizes the connector, e.g. replays recorded responses in tests
+type Option func(o *options)
+
+type options struct {
+	transport http.RoundTripper
+	noCache   bool
+}
+
+// WithTransport replaces the transport of the underlying http client, see recorder package
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = rt
+	}
+}
+
+// WithoutCache requests are never conditional and validators are not saved, e.g. for dry runs,
+// which must not make the next crawls skip the content
+func WithoutCache() Option {
+	return func(o *options) {
+		o.noCache = true
+	}
+}
+
+func New(cfg config.Config, opts ...Option) Connector {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	log := cfg.Logging().WithField("service", "[CONN]")
+	client := &http.Client{
+		Timeout:   cfg.RequestTimeout(),
+		Transport: o.transport,
+	}
+
+	kv := store.New(cfg).KVProvider()
+	c := &connector{
+		log:       log,
+		client:    client,
+		userAgent: cfg.UserAgent(),
+		robots:    newRobotsChecker(log, client, kv, cfg.UserAgent(), cfg.RobotsTTL()),
 	}
+	if !o.noCache {
+		c.cache = newHTTPCache(log, kv, cfg.ValidatorsTTL(), cfg.BodyCacheTTL())
+	}
+	return c
 }
 
 func (c connector) Poll(ctx context.Context, r PollParams) (io.Reader, int, error) {
-	c.log.WithField("params", r.Params.Encode()).Debugf("Requesting, %s%s...", r.Url, r.Path)
+	resp, err := c.Fetch(ctx, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Body, resp.StatusCode, nil
+}
+
+func (c connector) Fetch(ctx context.Context, r PollParams) (*Response, error) {
+	c.log.WithField("params", redactParams(r.Params).Encode()).Debugf("Requesting, %s%s...", r.Url, r.Path)
+
+	reqURL := fmt.Sprintf("%s%s", r.Url, r.Path)
+	if len(r.Params) > 0 {
+		reqURL = fmt.Sprintf("%s?%s", reqURL, r.Params.Encode())
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s?%s", r.Url, r.Path, r.Params.Encode()), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, 0, errors.Wrap(err, "failed to create new get request")
+		return nil, errors.Wrap(err, "failed to create new get request")
+	}
+
+	if !r.SkipRobots {
+		if err := c.robots.check(ctx, req.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	req.Header = c.headers(r.Headers)
+
+	// validators are sent if the caller handles 304 itself, or the cached body can answer it
+	cached := c.cache.get(ctx, reqURL)
+	conditional := cached != nil && (r.Conditional || cached.hasBody(time.Now()))
+	if conditional {
+		cached.setValidators(req.Header)
 	}
 
-	req.Header = r.Headers
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, 0, errors.Wrap(err, "failed to do get client request")
+		return nil, errors.Wrap(err, "failed to do get client request")
 	}
 
-	return resp.Body, resp.StatusCode, nil
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		resp.Body.Close()
+		c.log.Debugf("Not modified, %s...", reqURL)
+
+		if r.Conditional {
+			return notModifiedResponse(resp.Header), nil
+		}
+		return &Response{
+			Body:       bytes.NewReader(cached.Body),
+			StatusCode: http.StatusOK,
+			Header:     resp.Header,
+		}, nil
+	}
+
+	if resp.StatusCode == http.StatusOK && r.Conditional {
+		body, err := c.cache.store(ctx, reqURL, resp)
+		if err != nil {
+			return nil, err
+		}
+		return &Response{
+			Body:       bytes.NewReader(body),
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+		}, nil
+	}
+
+	return &Response{
+		Body:       resp.Body,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}, nil
 }
 
 func (c connector) Post(ctx context.Context, r RequestParams) (io.Reader, int, error) {
@@ -58,7 +163,7 @@ func (c connector) Post(ctx context.Context, r RequestParams) (io.Reader, int, e
 		return nil, 0, errors.Wrap(err, "failed to create new post request")
 	}
 
-	req.Header = r.Headers
+	req.Header = c.headers(r.Headers)
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, 0, errors.Wrap(err, "failed to do post client request")
@@ -66,3 +171,33 @@ func (c connector) Post(ctx context.Context, r RequestParams) (io.Reader, int, e
 
 	return resp.Body, resp.StatusCode, nil
 }
+
+// headers sets identifying user agent, unless the caller has set its own
+func (c connector) headers(h http.Header) http.Header {
+	headers := h.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	if headers.Get("User-Agent") == "" {
+		headers.Set("User-Agent", c.userAgent)
+	}
+	return headers
+}
+
+// credentialParams parts of the query param names carrying credentials, e.g. auth_token of CryptoPanic
+var credentialParams = []string{"token", "key", "secret", "password", "signature"}
+
+// redactParams params safe to log, values of the credential params are hidden
+func redactParams(params url.Values) url.Values {
+	redacted := make(url.Values, len(params))
+	for name, values := range params {
+		redacted[name] = values
+		for _, p := range credentialParams {
+			if strings.Contains(strings.ToLower(name), p) {
+				redacted[name] = []string{"REDACTED"}
+				break
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,45 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+type rateLimited struct {
+	Connector
+
+	rl *rate.Limiter
+}
+
+// WithRateLimit limits connector to at most rps requests per second
+func WithRateLimit(conn Connector, rps int) Connector {
+	return &rateLimited{
+		Connector: conn,
+		rl:        rate.NewLimiter(rate.Every(time.Second/time.Duration(rps)), 1),
+	}
+}
+
+func (c rateLimited) Poll(ctx context.Context, r PollParams) (io.Reader, int, error) {
+	if err := c.rl.Wait(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to wait for the rate limiter")
+	}
+	return c.Connector.Poll(ctx, r)
+}
+
+func (c rateLimited) Fetch(ctx context.Context, r PollParams) (*Response, error) {
+	if err := c.rl.Wait(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to wait for the rate limiter")
+	}
+	return c.Connector.Fetch(ctx, r)
+}
+
+func (c rateLimited) Post(ctx context.Context, r RequestParams) (io.Reader, int, error) {
+	if err := c.rl.Wait(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to wait for the rate limiter")
+	}
+	return c.Connector.Post(ctx, r)
+}
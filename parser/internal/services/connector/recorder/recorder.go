@@ -0,0 +1,131 @@
+// Package recorder captures real http responses into fixture files once and replays them offline,
+// so crawlers can be tested without network access.
+//
+// Fixtures are plain http response dumps named after the request, e.g.
+// GET_www.coindesk.com_arc_outboundfeeds_rss.http, requests with a query or a body get a short hash suffix.
+// Set RECORD_FIXTURES=1 to (re)record them from the live sites.
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const EnvRecord = "RECORD_FIXTURES"
+
+type Mode int
+
+const (
+	// Replay serves responses from fixtures, requests without a fixture fail
+	Replay Mode = iota
+	// Record does real requests and stores responses as fixtures
+	Record
+)
+
+// ModeFromEnv Record if RECORD_FIXTURES is set, Replay otherwise
+func ModeFromEnv() Mode {
+	if os.Getenv(EnvRecord) != "" {
+		return Record
+	}
+	return Replay
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9.\-]+`)
+
+type recorder struct {
+	mode Mode
+	dir  string
+	// real transport used for recording
+	next http.RoundTripper
+}
+
+// New recorder transport keeping fixtures in dir, next is used for real requests, http.DefaultTransport if nil
+func New(mode Mode, dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recorder{
+		mode: mode,
+		dir:  dir,
+		next: next,
+	}
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, errors.Wrap(err, "failed to read request body")
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	path := filepath.Join(r.dir, FixtureName(req, reqBody))
+	if r.mode == Record {
+		return r.record(req, path)
+	}
+	return replay(req, path)
+}
+
+func (r *recorder) record(req *http.Request, path string) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dump response")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to create fixtures dir")
+	}
+	if err := os.WriteFile(path, dump, 0o644); err != nil {
+		return nil, errors.Wrapf(err, "failed to write fixture %s", path)
+	}
+	return readResponse(req, dump)
+}
+
+func replay(req *http.Request, path string) (*http.Response, error) {
+	dump, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no fixture for %s %s, record it with %s=1", req.Method, req.URL, EnvRecord)
+	}
+	return readResponse(req, dump)
+}
+
+func readResponse(req *http.Request, dump []byte) (*http.Response, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(dump)), req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read fixture response")
+	}
+	return resp, nil
+}
+
+// FixtureName file name of the fixture for the request
+func FixtureName(req *http.Request, body []byte) string {
+	name := req.Method + "_" + req.URL.Host + strings.ReplaceAll(strings.TrimSuffix(req.URL.Path, "/"), "/", "_")
+	name = unsafeChars.ReplaceAllString(name, "_")
+
+	if req.URL.RawQuery != "" || len(body) > 0 {
+		h := sha256.New()
+		h.Write([]byte(req.URL.Query().Encode()))
+		h.Write(body)
+		name += "_" + hex.EncodeToString(h.Sum(nil))[:8]
+	}
+	return name + ".http"
+}
@@ -0,0 +1,57 @@
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(w, "hello from "+r.URL.Path)
+	}))
+
+	dir := t.TempDir()
+	recording := &http.Client{Transport: New(Record, dir, nil)}
+
+	resp, err := recording.Get(server.URL + "/feed?page=1")
+	require.NoError(t, err)
+	recorded, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// replay must not touch the network
+	server.Close()
+
+	replaying := &http.Client{Transport: New(Replay, dir, nil)}
+	resp, err = replaying.Get(server.URL + "/feed?page=1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	replayed, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "hello from /feed", string(recorded))
+	require.Equal(t, string(recorded), string(replayed))
+	require.Equal(t, `"v1"`, resp.Header.Get("ETag"), "expected headers to be replayed")
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	client := &http.Client{Transport: New(Replay, t.TempDir(), nil)}
+
+	_, err := client.Get("https://example.com/missing")
+	require.ErrorContains(t, err, EnvRecord, "expected missing fixture error pointing to the record switch")
+}
+
+func TestFixtureName(t *testing.T) {
+	plain := httptest.NewRequest(http.MethodGet, "https://www.coindesk.com/arc/outboundfeeds/rss/", nil)
+	require.Equal(t, "GET_www.coindesk.com_arc_outboundfeeds_rss.http", FixtureName(plain, nil))
+
+	first := httptest.NewRequest(http.MethodGet, "https://api.example.com/posts?page=1", nil)
+	second := httptest.NewRequest(http.MethodGet, "https://api.example.com/posts?page=2", nil)
+	require.NotEqual(t, FixtureName(first, nil), FixtureName(second, nil),
+		"expected requests with different queries to have different fixtures")
+}
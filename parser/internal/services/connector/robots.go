@@ -0,0 +1,303 @@
+package connector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common/data"
+	"common/data/queriers"
+)
+
+var ErrDisallowed = errors.New("url is disallowed by robots.txt")
+
+// maxRobotsSize robots.txt beyond this size is truncated, as RFC 9309 suggests (500 KiB)
+const maxRobotsSize = 500 * 1024
+
+// robotsRules rules of the robots.txt group that applies to our user agent
+type robotsRules struct {
+	Rules      []robotsRule  `json:"rules"`
+	CrawlDelay time.Duration `json:"crawl_delay"`
+}
+
+type robotsRule struct {
+	Allow bool   `json:"allow"`
+	Path  string `json:"path"`
+}
+
+type robotsChecker struct {
+	log *logrus.Entry
+
+	client    *http.Client
+	kv        queriers.KVProvider
+	userAgent string
+	ttl       time.Duration
+
+	mu sync.Mutex
+	// host : the earliest time next request is allowed, according to Crawl-delay
+	nextRequest map[string]time.Time
+}
+
+func newRobotsChecker(log *logrus.Entry, client *http.Client, kv queriers.KVProvider, userAgent string, ttl time.Duration) *robotsChecker {
+	return &robotsChecker{
+		log:         log,
+		client:      client,
+		kv:          kv,
+		userAgent:   userAgent,
+		ttl:         ttl,
+		nextRequest: make(map[string]time.Time),
+	}
+}
+
+// check returns ErrDisallowed if url is disallowed for us, waits for the Crawl-delay of the host otherwise
+func (c *robotsChecker) check(ctx context.Context, u *url.URL) error {
+	rules, err := c.rules(ctx, u)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get robots.txt rules for host: %s", u.Host)
+	}
+
+	if !rules.allowed(requestPath(u)) {
+		return errors.Wrapf(ErrDisallowed, "url: %s", u.String())
+	}
+
+	return c.waitCrawlDelay(ctx, u.Host, rules.CrawlDelay)
+}
+
+func (c *robotsChecker) rules(ctx context.Context, u *url.URL) (*robotsRules, error) {
+	key := fmt.Sprintf("robots/%s://%s", u.Scheme, u.Host)
+
+	var rules robotsRules
+	err := c.kv.GetStruct(ctx, key, &rules)
+	if err == nil {
+		return &rules, nil
+	}
+	if !errors.Is(err, data.ErrNotFound) {
+		return nil, errors.Wrap(err, "failed to get cached robots.txt")
+	}
+
+	fetched, err := c.fetch(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.kv.SetStruct(ctx, key, fetched, c.ttl); err != nil {
+		return nil, errors.Wrap(err, "failed to cache robots.txt")
+	}
+	return fetched, nil
+}
+
+func (c *robotsChecker) fetch(ctx context.Context, u *url.URL) (*robotsRules, error) {
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	c.log.Debugf("Requesting, %s...", robotsURL.String())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create robots.txt request")
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request robots.txt")
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return parseRobots(io.LimitReader(resp.Body, maxRobotsSize), c.userAgent), nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		// unavailable robots.txt means no restrictions
+		return &robotsRules{}, nil
+	default:
+		// server errors are transient, we should neither crawl nor give up on the url
+		return nil, errors.Errorf("robots.txt returned status code: %d", resp.StatusCode)
+	}
+}
+
+func (c *robotsChecker) waitCrawlDelay(ctx context.Context, host string, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	at := c.nextRequest[host]
+	if at.Before(now) {
+		at = now
+	}
+	c.nextRequest[host] = at.Add(delay)
+	c.mu.Unlock()
+
+	wait := at.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to wait for the crawl delay")
+	}
+}
+
+// parseRobots picks the groups matching the user agent product token, the `*` groups otherwise,
+// several matching groups are combined into one, as RFC 9309 requires
+func parseRobots(r io.Reader, userAgent string) *robotsRules {
+	token := strings.ToLower(strings.TrimSpace(strings.SplitN(userAgent, "/", 2)[0]))
+
+	var (
+		matched, wildcard *robotsRules
+
+		groupAgents []string
+		group       *robotsRules
+		// agent lines following each other open the same group, any rule line closes the agents list
+		inAgents bool
+	)
+
+	flush := func() {
+		if group == nil {
+			return
+		}
+		for _, agent := range groupAgents {
+			// the product token is matched as a whole, so short group names like `bot` don't apply to us
+			switch agent {
+			case "*":
+				wildcard = wildcard.merge(group)
+			case token:
+				matched = matched.merge(group)
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				flush()
+				groupAgents = nil
+				group = &robotsRules{}
+				inAgents = true
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			// empty disallow means allow everything
+			if group == nil || value == "" {
+				continue
+			}
+			group.Rules = append(group.Rules, robotsRule{Allow: key == "allow", Path: value})
+		case "crawl-delay":
+			inAgents = false
+			if group == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				group.CrawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+	flush()
+
+	if matched != nil {
+		return matched
+	}
+	if wildcard != nil {
+		return wildcard
+	}
+	return &robotsRules{}
+}
+
+// merge combines the groups for the same user agent, the longest Crawl-delay is kept
+func (r *robotsRules) merge(group *robotsRules) *robotsRules {
+	if r == nil {
+		return &robotsRules{
+			Rules:      append([]robotsRule(nil), group.Rules...),
+			CrawlDelay: group.CrawlDelay,
+		}
+	}
+
+	r.Rules = append(r.Rules, group.Rules...)
+	if group.CrawlDelay > r.CrawlDelay {
+		r.CrawlDelay = group.CrawlDelay
+	}
+	return r
+}
+
+// allowed the longest matching rule wins, allow wins on equal length
+func (r robotsRules) allowed(path string) bool {
+	allow := true
+	longest := -1
+	for _, rule := range r.Rules {
+		if !matchRobotsPattern(rule.Path, path) {
+			continue
+		}
+		if len(rule.Path) > longest || (len(rule.Path) == longest && rule.Allow) {
+			longest = len(rule.Path)
+			allow = rule.Allow
+		}
+	}
+	return allow
+}
+
+// matchRobotsPattern supports `*` (any sequence) and trailing `$` (end of path) special characters
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	if len(parts) == 1 {
+		return !anchored || path == parts[0]
+	}
+
+	pos := len(parts[0])
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+
+	last := parts[len(parts)-1]
+	if anchored {
+		return len(path)-len(last) >= pos && strings.HasSuffix(path, last)
+	}
+	return strings.Contains(path[pos:], last)
+}
+
+func requestPath(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path = fmt.Sprintf("%s?%s", path, u.RawQuery)
+	}
+	return path
+}
@@ -0,0 +1,152 @@
+package connector
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcRobots example of RFC 9309, section 5.1
+const rfcRobots = `
+User-Agent: *
+Disallow: *.gif$
+Disallow: /example/
+Allow: /publications/
+
+User-Agent: foobot
+Disallow:/
+Allow:/example/page.html
+Allow:/example/allowed.gif
+
+User-Agent: barbot
+User-Agent: bazbot
+Disallow: /example/page.html
+
+User-Agent: quxbot
+`
+
+func TestParseRobotsGroups(t *testing.T) {
+	cases := []struct {
+		userAgent string
+		path      string
+		allowed   bool
+	}{
+		{"foobot", "/example/page.html", true},
+		{"FooBot/1.0", "/example/allowed.gif", true},
+		{"foobot", "/example/other.html", false},
+		{"foobot", "/", false},
+		{"barbot", "/example/page.html", false},
+		{"bazbot", "/example/page.html", false},
+		{"bazbot", "/example/other.html", true},
+		// the group without rules allows everything
+		{"quxbot", "/example/page.html", true},
+		// the rest fall back to the * group
+		{"otherbot", "/example/page.html", false},
+		{"otherbot", "/images/logo.gif", false},
+		{"otherbot", "/publications/list", true},
+		// the product token is matched as a whole
+		{"foobotty", "/example/other.html", false},
+		{"foobotty", "/other.html", true},
+	}
+
+	for _, c := range cases {
+		rules := parseRobots(strings.NewReader(rfcRobots), c.userAgent)
+		require.Equal(t, c.allowed, rules.allowed(c.path), "%s: %s", c.userAgent, c.path)
+	}
+}
+
+func TestParseRobotsShortGroupName(t *testing.T) {
+	rules := parseRobots(strings.NewReader("User-agent: bot\nDisallow: /\n"), "cryptonewsbot/1.0")
+	require.True(t, rules.allowed("/news"), "group of another product token must not apply")
+}
+
+func TestParseRobotsMergesGroups(t *testing.T) {
+	const robots = `
+User-agent: cryptonewsbot
+Disallow: /private/
+Crawl-delay: 1
+
+User-agent: *
+Disallow: /
+
+User-agent: cryptonewsbot
+Disallow: /drafts/
+Crawl-delay: 2.5
+`
+	rules := parseRobots(strings.NewReader(robots), "CryptoNewsBot/1.0")
+	require.False(t, rules.allowed("/private/a"))
+	require.False(t, rules.allowed("/drafts/a"))
+	require.True(t, rules.allowed("/news/a"))
+	require.Equal(t, 2500*time.Millisecond, rules.CrawlDelay)
+}
+
+// TestMatchRobotsPattern examples of RFC 9309, section 2.2.3, and of the Google robots.txt spec
+func TestMatchRobotsPattern(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		matches   []string
+		unmatched []string
+	}{
+		{
+			pattern:   "/fish",
+			matches:   []string{"/fish", "/fish.html", "/fish/salmon.html", "/fishheads", "/fishheads/yummy.html", "/fish.php?id=anything"},
+			unmatched: []string{"/Fish.asp", "/catfish", "/?id=fish", "/desert/fish"},
+		},
+		{
+			pattern:   "/fish*",
+			matches:   []string{"/fish", "/fish.html", "/fish/salmon.html", "/fishheads", "/fish.php?id=anything"},
+			unmatched: []string{"/Fish.asp", "/catfish", "/?id=fish"},
+		},
+		{
+			pattern:   "/fish/",
+			matches:   []string{"/fish/", "/fish/?id=anything", "/fish/salmon.htm"},
+			unmatched: []string{"/fish", "/fish.html", "/animals/fish/", "/Fish/Salmon.asp"},
+		},
+		{
+			pattern:   "/*.php",
+			matches:   []string{"/index.php", "/filename.php", "/folder/filename.php", "/folder/filename.php?parameters", "/folder/any.php.file.html", "/filename.php/"},
+			unmatched: []string{"/", "/windows.PHP"},
+		},
+		{
+			pattern:   "/*.php$",
+			matches:   []string{"/filename.php", "/folder/filename.php"},
+			unmatched: []string{"/filename.php?parameters", "/filename.php/", "/filename.php5", "/windows.PHP"},
+		},
+		{
+			pattern:   "/fish*.php",
+			matches:   []string{"/fish.php", "/fishheads/catfish.php?parameters"},
+			unmatched: []string{"/Fish.PHP"},
+		},
+	}
+
+	for _, c := range cases {
+		for _, path := range c.matches {
+			require.True(t, matchRobotsPattern(c.pattern, path), "%s should match %s", c.pattern, path)
+		}
+		for _, path := range c.unmatched {
+			require.False(t, matchRobotsPattern(c.pattern, path), "%s should not match %s", c.pattern, path)
+		}
+	}
+}
+
+func TestRobotsLongestMatch(t *testing.T) {
+	cases := []struct {
+		rules   []robotsRule
+		path    string
+		allowed bool
+	}{
+		{[]robotsRule{{Allow: true, Path: "/p"}, {Allow: false, Path: "/"}}, "/page", true},
+		// allow wins on equal length
+		{[]robotsRule{{Allow: true, Path: "/folder"}, {Allow: false, Path: "/folder"}}, "/folder/page", true},
+		{[]robotsRule{{Allow: true, Path: "/page"}, {Allow: false, Path: "/*.htm"}}, "/page.htm", false},
+		{[]robotsRule{{Allow: true, Path: "/$"}, {Allow: false, Path: "/"}}, "/", true},
+		{[]robotsRule{{Allow: true, Path: "/$"}, {Allow: false, Path: "/"}}, "/page.htm", false},
+		{nil, "/anything", true},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.allowed, robotsRules{Rules: c.rules}.allowed(c.path), "%v: %s", c.rules, c.path)
+	}
+}
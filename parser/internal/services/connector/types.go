@@ -2,6 +2,7 @@ package connector
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 )
@@ -11,6 +12,10 @@ type PollParams struct {
 	Path    string
 	Params  url.Values
 	Headers http.Header
+	// SkipRobots robots.txt is not checked, the caller decides when it does not apply
+	SkipRobots bool
+	// Conditional caller treats 304 Not Modified as no new content, validators of the url are kept between requests
+	Conditional bool
 }
 
 type RequestParams struct {
@@ -19,3 +24,9 @@ type RequestParams struct {
 	Body    json.RawMessage
 	Headers http.Header
 }
+
+type Response struct {
+	Body       io.Reader
+	StatusCode int
+	Header     http.Header
+}
@@ -0,0 +1,70 @@
+package crawler
+
+import (
+	"context"
+	"sync"
+)
+
+type deferredKey struct{}
+
+type deferredAction struct {
+	commit   func(ctx context.Context)
+	rollback func(ctx context.Context)
+}
+
+// Deferred actions of a crawl that depend on its results being stored, e.g. saving the http validators
+// or giving back the claimed remote tasks, a crawl that failed to store its results must not lose them
+type Deferred struct {
+	mu      sync.Mutex
+	actions []deferredAction
+}
+
+// Defer crawls run with the returned context keep their actions until Commit or Rollback
+func Defer(ctx context.Context) (context.Context, *Deferred) {
+	d := &Deferred{}
+	return context.WithValue(ctx, deferredKey{}, d), d
+}
+
+// AfterStore registers the actions of the crawl results, either of them may be nil,
+// false means the crawl is not deferred and the caller commits right away
+func AfterStore(ctx context.Context, commit, rollback func(ctx context.Context)) bool {
+	d, ok := ctx.Value(deferredKey{}).(*Deferred)
+	if !ok {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.actions = append(d.actions, deferredAction{commit: commit, rollback: rollback})
+	return true
+}
+
+// Commit to be called once the crawl results are stored
+func (d *Deferred) Commit(ctx context.Context) {
+	for _, a := range d.take() {
+		if a.commit != nil {
+			a.commit(ctx)
+		}
+	}
+}
+
+// Rollback to be called if the crawl results are dropped
+func (d *Deferred) Rollback(ctx context.Context) {
+	for _, a := range d.take() {
+		if a.rollback != nil {
+			a.rollback(ctx)
+		}
+	}
+}
+
+func (d *Deferred) take() []deferredAction {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	actions := d.actions
+	d.actions = nil
+	return actions
+}
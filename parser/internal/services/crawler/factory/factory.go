@@ -0,0 +1,90 @@
+package factory
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"parser/internal/config"
+	browse_ai_crawler "parser/internal/services/browse-ai-crawler"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+	crypto_panic_crawler "parser/internal/services/crypto-panic-crawler"
+	discourse_crawler "parser/internal/services/discourse-crawler"
+	html_listing_crawler "parser/internal/services/html-listing-crawler"
+	json_api_crawler "parser/internal/services/json-api-crawler"
+	reddit_crawler "parser/internal/services/reddit-crawler"
+	rss_crawler "parser/internal/services/rss-crawler"
+	sitemap_crawler "parser/internal/services/sitemap-crawler"
+)
+
+type constructor func(cfg config.Config, conn connector.Connector, keys ...string) crawler.Crawler
+
+var constructors = map[string]constructor{
+	browse_ai_crawler.BrowseAI:       browse_ai_crawler.NewCrawler,
+	rss_crawler.RSS:                  rss_crawler.NewCrawler,
+	html_listing_crawler.HTMLListing: html_listing_crawler.NewCrawler,
+	json_api_crawler.JSONAPI:         json_api_crawler.NewCrawler,
+	crypto_panic_crawler.CryptoPanic: crypto_panic_crawler.NewCrawler,
+	sitemap_crawler.Sitemap:          sitemap_crawler.NewCrawler,
+	reddit_crawler.Reddit:            reddit_crawler.NewCrawler,
+	discourse_crawler.Discourse:      discourse_crawler.NewCrawler,
+}
+
+// Source titles crawler with its own schedule
+type Source struct {
+	crawler.Crawler
+
+	Name       string
+	Type       string
+	CrawlEvery time.Duration
+}
+
+// New builds crawlers declared in the `crawlers` config section, disabled ones are skipped
+func New(cfg config.Config) []Source {
+	log := cfg.Logging().WithField("service", "[CRAWLER-FACTORY]")
+
+	sources := make([]Source, 0, len(cfg.Crawlers()))
+	for _, c := range cfg.Crawlers() {
+		if c.Disabled {
+			log.WithField("crawler", c.Name).Info("Crawler is disabled, skipping...")
+			continue
+		}
+
+		src, err := NewSource(cfg, c)
+		if err != nil {
+			panic(err)
+		}
+		sources = append(sources, src)
+	}
+	return sources
+}
+
+// ByName builds the crawler declared under the name, disabled ones included, e.g. to debug them
+func ByName(cfg config.Config, name string, opts ...connector.Option) (Source, error) {
+	for _, c := range cfg.Crawlers() {
+		if c.Name == name {
+			return NewSource(cfg, c, opts...)
+		}
+	}
+	return Source{}, errors.Errorf("unknown crawler: %s", name)
+}
+
+func NewSource(cfg config.Config, c config.CrawlerConfig, opts ...connector.Option) (Source, error) {
+	newCrawler, ok := constructors[c.Type]
+	if !ok {
+		return Source{}, errors.Errorf("unknown crawler type: %s, for crawler: %s", c.Type, c.Name)
+	}
+
+	conn := connector.New(cfg, opts...)
+	if c.RateLimit > 0 {
+		conn = connector.WithRateLimit(conn, c.RateLimit)
+	}
+
+	return Source{
+		Crawler:    newCrawler(cfg, conn, c.Keys()...),
+		Name:       c.Name,
+		Type:       c.Type,
+		CrawlEvery: c.CrawlEvery,
+	}, nil
+}
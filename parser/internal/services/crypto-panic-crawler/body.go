@@ -0,0 +1,87 @@
+package crypto_panic_crawler
+
+import (
+	"time"
+
+	"common/convert"
+	"common/data/model"
+	"common/hash"
+	"parser/internal/services/crawler"
+)
+
+var _ crawler.ParsedBody = body{}
+
+type rawBody struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []body  `json:"results"`
+}
+
+type body struct {
+	ID          int64      `json:"id"`
+	Kind        string     `json:"kind"`
+	Domain      string     `json:"domain"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	URL         string     `json:"url"`
+	OriginalURL string     `json:"original_url"`
+	PublishedAt *time.Time `json:"published_at"`
+	Source      struct {
+		Title  string `json:"title"`
+		Region string `json:"region"`
+		Domain string `json:"domain"`
+	} `json:"source"`
+	Currencies []struct {
+		Code  string `json:"code"`
+		Title string `json:"title"`
+		Slug  string `json:"slug"`
+	} `json:"currencies"`
+	Metadata *struct {
+		Description string `json:"description"`
+	} `json:"metadata"`
+}
+
+func (b body) ToModel() any {
+	// original_url is only exposed to some plans, otherwise we have to go through the cryptopanic redirect page
+	url := b.OriginalURL
+	if url == "" {
+		url = b.URL
+	}
+
+	source := b.Source.Domain
+	if source == "" {
+		source = CryptoPanic
+	}
+
+	var summary *string
+	if b.Metadata != nil && b.Metadata.Description != "" {
+		summary = convert.ToPtr(b.Metadata.Description)
+	}
+
+	var releaseDate *time.Time
+	if b.PublishedAt != nil {
+		releaseDate = convert.ToPtr(b.PublishedAt.UTC())
+	}
+
+	coins := make([]model.Coin, len(b.Currencies))
+	for i, c := range b.Currencies {
+		coins[i] = model.Coin{
+			Code:  c.Code,
+			Title: c.Title,
+			Slug:  c.Slug,
+		}
+	}
+
+	return model.Title{
+		Title:       &b.Title,
+		Summary:     summary,
+		Hash:        convert.ToPtr(hash.Hash(b.Title, url)),
+		URL:         &url,
+		ReleaseDate: releaseDate,
+		Status:      convert.ToPtr(model.StatusPending),
+		Source:      &source,
+		Kind:        convert.ToPtr(model.KindNews),
+		Coins:       coins,
+	}
+}
@@ -0,0 +1,120 @@
+package crypto_panic_crawler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+)
+
+const CryptoPanic = "crypto_panic"
+
+const defaultPages = 1
+
+type CryptoPanicCrawler struct {
+	log *logrus.Entry
+
+	authToken string
+	url       string
+	path      string
+
+	// optional API filters, passed as is, see https://cryptopanic.com/developers/api/
+	currencies string
+	filter     string
+	kind       string
+	regions    string
+	pages      int
+
+	conn connector.Connector
+}
+
+// NewCrawler keys point to the crypto panic credentials, e.g. [crypto_panic]
+func NewCrawler(cfg config.Config, conn connector.Connector, keys ...string) crawler.Crawler {
+	pages, err := strconv.Atoi(cfg.OptionalCredentials(config.CredentialsPath(keys, "pages")...))
+	if err != nil || pages < 1 {
+		pages = defaultPages
+	}
+
+	return &CryptoPanicCrawler{
+		log: cfg.Logging().WithField("service", "[CRYPTO-PANIC-CRAWLER]"),
+
+		authToken: cfg.Credentials(config.CredentialsPath(keys, "auth_token")...),
+		url:       cfg.Credentials(config.CredentialsPath(keys, "url")...),
+		path:      cfg.Credentials(config.CredentialsPath(keys, "path")...),
+
+		currencies: cfg.OptionalCredentials(config.CredentialsPath(keys, "currencies")...),
+		filter:     cfg.OptionalCredentials(config.CredentialsPath(keys, "filter")...),
+		kind:       cfg.OptionalCredentials(config.CredentialsPath(keys, "kind")...),
+		regions:    cfg.OptionalCredentials(config.CredentialsPath(keys, "regions")...),
+		pages:      pages,
+
+		conn: conn,
+	}
+}
+
+func (c CryptoPanicCrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	bodies := make([]crawler.ParsedBody, 0, 20*c.pages)
+
+	for page := 1; page <= c.pages; page++ {
+		rawPostsBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+			Url:        c.url,
+			Path:       c.path,
+			Params:     c.params(page),
+			SkipRobots: true,
+		})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to poll crypto-panic API")
+		}
+
+		if statusCode != http.StatusOK {
+			// the first page is mandatory, later pages are best effort
+			if page == 1 {
+				return nil, statusCode, nil
+			}
+			c.log.WithField("status-code", statusCode).Warnf("Stopping pagination at page %d...", page)
+			break
+		}
+
+		var postsBody rawBody
+		if err := json.NewDecoder(rawPostsBody).Decode(&postsBody); err != nil {
+			return nil, statusCode, errors.Wrap(err, "failed to decode posts response body")
+		}
+
+		for _, post := range postsBody.Results {
+			bodies = append(bodies, post)
+		}
+
+		if postsBody.Next == nil {
+			break
+		}
+	}
+
+	return bodies, http.StatusOK, nil
+}
+
+func (c CryptoPanicCrawler) params(page int) url.Values {
+	params := url.Values{
+		"auth_token": []string{c.authToken},
+		"public":     []string{"true"},
+		"page":       []string{strconv.Itoa(page)},
+	}
+	for k, v := range map[string]string{
+		"currencies": c.currencies,
+		"filter":     c.filter,
+		"kind":       c.kind,
+		"regions":    c.regions,
+	} {
+		if v != "" {
+			params.Set(k, v)
+		}
+	}
+	return params
+}
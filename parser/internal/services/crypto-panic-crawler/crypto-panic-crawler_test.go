@@ -0,0 +1,83 @@
+package crypto_panic_crawler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+	"parser/internal/services/crawler"
+	"parser/internal/testutil"
+)
+
+// fixtures are named after the query, so a missing or a wrong param fails the replay
+const testConfig = `
+crawlers:
+  - name: crypto_panic
+    type: crypto_panic
+    credentials: [ crypto_panic ]
+service_providers:
+  services:
+    crypto_panic:
+      auth_token: token
+      url: https://cryptopanic.com
+      path: /api/v1/posts/
+      kind: news
+      filter: hot
+      currencies: BTC,ETH
+      pages: 3
+`
+
+func TestCrawlPages(t *testing.T) {
+	c, _ := testutil.Crawler(t, testConfig, "crypto-panic", NewCrawler, "crypto_panic")
+
+	// the second page has no next, so the third one is never requested
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	titles := crawler.ToModelBatch[model.Title](bodies)
+	require.Len(t, titles, 3)
+
+	first := titles[0]
+	require.Equal(t, "Bitcoin Tops $30K as ETF Hopes Build", *first.Title)
+	require.Equal(t, "https://www.coindesk.com/markets/2023/10/17/bitcoin-tops-30k-as-etf-hopes-build/", *first.URL,
+		"expected original url")
+	require.Equal(t, "coindesk.com", *first.Source)
+	require.Equal(t, "The largest cryptocurrency rallied for the first time since June.", *first.Summary)
+	require.NotNil(t, first.ReleaseDate)
+	require.Equal(t, time.Date(2023, time.October, 17, 8, 30, 0, 0, time.UTC), *first.ReleaseDate)
+	require.Equal(t, []model.Coin{{Code: "BTC", Title: "Bitcoin", Slug: "bitcoin"}}, first.Coins)
+
+	// original url and source domain are not exposed to every plan
+	second := titles[1]
+	require.Equal(t, "https://cryptopanic.com/news/102/Ethereum-Developers-Schedule-Next-Upgrade", *second.URL)
+	require.Equal(t, CryptoPanic, *second.Source)
+	require.Nil(t, second.Summary)
+	require.Equal(t, []model.Coin{{Code: "ETH", Title: "Ethereum", Slug: "ethereum"}}, second.Coins)
+
+	third := titles[2]
+	require.Equal(t, "https://cointelegraph.com/news/bitcoin-and-ether-options-expire", *third.URL)
+	require.Equal(t, []model.Coin{
+		{Code: "BTC", Title: "Bitcoin", Slug: "bitcoin"},
+		{Code: "ETH", Title: "Ethereum", Slug: "ethereum"},
+	}, third.Coins)
+}
+
+func TestParams(t *testing.T) {
+	cfg := testutil.Config(t, testConfig)
+	c := NewCrawler(cfg, nil, "crypto_panic").(*CryptoPanicCrawler)
+
+	params := c.params(2)
+	require.Equal(t, "token", params.Get("auth_token"))
+	require.Equal(t, "true", params.Get("public"))
+	require.Equal(t, "2", params.Get("page"))
+	require.Equal(t, "BTC,ETH", params.Get("currencies"))
+	require.Equal(t, "hot", params.Get("filter"))
+	require.Equal(t, "news", params.Get("kind"))
+	// filters left out of the config are not sent
+	require.False(t, params.Has("regions"))
+}
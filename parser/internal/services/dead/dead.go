@@ -0,0 +1,103 @@
+package dead
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common"
+	"common/convert"
+	"common/data"
+	"common/data/model"
+	"common/data/queriers"
+	"common/data/store"
+	"parser/internal/config"
+)
+
+// Service manages titles that ran out of crawl attempts
+type Service interface {
+	// List writes dead titles to w
+	List(ctx context.Context, w io.Writer) error
+	// Requeue moves dead titles back to pending with a fresh attempts budget, all dead titles if ids are empty
+	Requeue(ctx context.Context, ids []uuid.UUID) (int, error)
+	// Purge gives up dead titles for good, all dead titles if ids are empty
+	Purge(ctx context.Context, ids []uuid.UUID) error
+}
+
+type service struct {
+	log *logrus.Entry
+
+	dataProvider store.DataProvider
+}
+
+func New(cfg config.Config) Service {
+	return &service{
+		log:          cfg.Logging().WithField("service", "[DEAD]"),
+		dataProvider: store.New(cfg),
+	}
+}
+
+func (s *service) deadTitles(ids []uuid.UUID) queriers.TitlesProvider {
+	provider := s.dataProvider.TitlesProvider().ByStatus(model.StatusDead)
+	if len(ids) > 0 {
+		provider = provider.ByIDs(ids)
+	}
+	return provider
+}
+
+func (s *service) List(ctx context.Context, w io.Writer) error {
+	titles, err := s.deadTitles(nil).Select(ctx)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			_, err = fmt.Fprintln(w, "no dead titles")
+			return err
+		}
+		return errors.Wrap(err, "failed to select dead titles")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSOURCE\tATTEMPTS\tURL\tLAST ERROR")
+	for _, t := range titles {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n",
+			t.ID,
+			convert.FromPtr(t.Source),
+			convert.FromPtr(t.Attempts),
+			convert.FromPtr(t.URL),
+			convert.FromPtr(t.LastError),
+		)
+	}
+	return tw.Flush()
+}
+
+func (s *service) Requeue(ctx context.Context, ids []uuid.UUID) (int, error) {
+	requeued, err := s.deadTitles(ids).Update(ctx, model.UpdateTitleParams{
+		Status:        convert.ToPtr(model.StatusPending),
+		Attempts:      convert.ToPtr(0),
+		NextAttemptAt: convert.ToPtr(common.CurrentTimestamp()),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to requeue dead titles")
+	}
+
+	s.log.WithField("count", len(requeued)).Info("requeued dead titles")
+	return len(requeued), nil
+}
+
+// Purge titles are not removed, but left as tombstones, otherwise the unique hash is freed
+// and the next crawl of the source inserts them again as pending
+func (s *service) Purge(ctx context.Context, ids []uuid.UUID) error {
+	purged, err := s.deadTitles(ids).Update(ctx, model.UpdateTitleParams{
+		Status: convert.ToPtr(model.StatusPurged),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to purge dead titles")
+	}
+
+	s.log.WithField("count", len(purged)).Info("purged dead titles")
+	return nil
+}
@@ -0,0 +1,62 @@
+package discourse_crawler
+
+import (
+	"time"
+
+	"common/convert"
+	"common/data/model"
+	"common/hash"
+	"parser/internal/services/crawler"
+)
+
+var _ crawler.ParsedBody = body{}
+
+// maxSummaryLen long opening posts are cut, the opening is enough to tell the sentiment
+const maxSummaryLen = 4000
+
+// rawTopicList latest.json or category listing, e.g. /c/markets/5.json
+type rawTopicList struct {
+	TopicList struct {
+		Topics []rawTopic `json:"topics"`
+	} `json:"topic_list"`
+}
+
+type rawTopic struct {
+	ID         int64      `json:"id"`
+	Title      string     `json:"title"`
+	Slug       string     `json:"slug"`
+	ReplyCount int        `json:"reply_count"`
+	LikeCount  int        `json:"like_count"`
+	CreatedAt  *time.Time `json:"created_at"`
+	Pinned     bool       `json:"pinned"`
+}
+
+// rawTopicPosts /t/<id>.json, the first post of the stream is the opening one
+type rawTopicPosts struct {
+	PostStream struct {
+		Posts []struct {
+			Cooked string `json:"cooked"`
+		} `json:"posts"`
+	} `json:"post_stream"`
+}
+
+type body struct {
+	title       string
+	url         string
+	summary     string
+	releaseDate *time.Time
+	source      string
+}
+
+func (b body) ToModel() any {
+	return model.Title{
+		Title:       &b.title,
+		Summary:     &b.summary,
+		Hash:        convert.ToPtr(hash.Hash(b.title, b.url)),
+		URL:         &b.url,
+		ReleaseDate: b.releaseDate,
+		Status:      convert.ToPtr(model.StatusPending),
+		Source:      &b.source,
+		Kind:        convert.ToPtr(model.KindCommunity),
+	}
+}
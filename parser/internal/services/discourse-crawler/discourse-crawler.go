@@ -0,0 +1,179 @@
+package discourse_crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+
+	"common/convert"
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+	"parser/internal/services/normalizer"
+)
+
+const Discourse = "discourse"
+
+const defaultMaxTopics = 20
+
+// DiscourseCrawler reads topics of the Discourse forum (or one of its categories) and their opening posts,
+// topics below the likes and replies thresholds are skipped
+type DiscourseCrawler struct {
+	log *logrus.Entry
+
+	url      string
+	category string
+	source   string
+
+	minLikes   int
+	minReplies int
+	maxTopics  int
+
+	normalizer normalizer.Normalizer
+	conn       connector.Connector
+}
+
+// NewCrawler forum keys point to the forum definition, e.g. [discourse, forums, ethresear_ch]
+func NewCrawler(cfg config.Config, conn connector.Connector, forumKeys ...string) crawler.Crawler {
+	maxTopics := threshold(cfg, forumKeys, "max_topics")
+	if maxTopics <= 0 {
+		maxTopics = defaultMaxTopics
+	}
+
+	return &DiscourseCrawler{
+		log: cfg.Logging().WithField("service", "[DISCOURSE-CRAWLER]").WithField("forum", forumKeys),
+
+		url:      strings.TrimSuffix(cfg.Credentials(config.CredentialsPath(forumKeys, "url")...), "/"),
+		category: strings.Trim(cfg.OptionalCredentials(config.CredentialsPath(forumKeys, "category")...), "/"),
+		source:   cfg.Credentials(config.CredentialsPath(forumKeys, "source")...),
+
+		minLikes:   threshold(cfg, forumKeys, "min_likes"),
+		minReplies: threshold(cfg, forumKeys, "min_replies"),
+		maxTopics:  maxTopics,
+
+		normalizer: normalizer.New(cfg),
+		conn:       conn,
+	}
+}
+
+func (c DiscourseCrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	// category is "<slug>/<id>", e.g. markets/5
+	path := "/latest.json"
+	if c.category != "" {
+		path = fmt.Sprintf("/c/%s.json", c.category)
+	}
+
+	rawListBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:  c.url,
+		Path: path,
+		// the JSON API is what the forum frontend uses, robots.txt covers the crawler-facing html only
+		SkipRobots: true,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to poll discourse topics")
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCode, nil
+	}
+
+	var list rawTopicList
+	if err := json.NewDecoder(rawListBody).Decode(&list); err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to decode topics body")
+	}
+
+	bodies := make([]crawler.ParsedBody, 0, c.maxTopics)
+	for _, topic := range list.TopicList.Topics {
+		if len(bodies) >= c.maxTopics {
+			break
+		}
+		// pinned topics are forum announcements and rules
+		if topic.Pinned || topic.LikeCount < c.minLikes || topic.ReplyCount < c.minReplies {
+			continue
+		}
+
+		title := strings.TrimSpace(topic.Title)
+		if title == "" {
+			continue
+		}
+
+		summary, err := c.openingPost(ctx, topic.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		releaseDate := topic.CreatedAt
+		if releaseDate != nil {
+			releaseDate = convert.ToPtr(releaseDate.UTC())
+		}
+
+		bodies = append(bodies, body{
+			title:       title,
+			url:         fmt.Sprintf("%s/t/%s/%d", c.url, topic.Slug, topic.ID),
+			summary:     summary,
+			releaseDate: releaseDate,
+			source:      c.source,
+		})
+	}
+
+	c.log.Debugf("Parsed %d of %d topics", len(bodies), len(list.TopicList.Topics))
+	return bodies, statusCode, nil
+}
+
+// openingPost text of the first post of the topic, empty if the topic is not available
+func (c DiscourseCrawler) openingPost(ctx context.Context, topicID int64) (string, error) {
+	rawPostsBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:        c.url,
+		Path:       fmt.Sprintf("/t/%d.json", topicID),
+		SkipRobots: true,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to poll discourse topic %d", topicID)
+	}
+
+	if statusCode != http.StatusOK {
+		c.log.WithFields(logrus.Fields{
+			"topic-id":    topicID,
+			"status-code": statusCode,
+		}).Warn("failed to get topic posts...")
+		return "", nil
+	}
+
+	var posts rawTopicPosts
+	if err := json.NewDecoder(rawPostsBody).Decode(&posts); err != nil {
+		return "", errors.Wrapf(err, "failed to decode topic %d posts", topicID)
+	}
+	if len(posts.PostStream.Posts) == 0 {
+		return "", nil
+	}
+
+	doc, err := html.Parse(strings.NewReader(posts.PostStream.Posts[0].Cooked))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse topic %d opening post", topicID)
+	}
+
+	text := c.normalizer.Text(doc)
+	if len(text) > maxSummaryLen {
+		text = strings.ToValidUTF8(text[:maxSummaryLen], "")
+	}
+	return text, nil
+}
+
+func threshold(cfg config.Config, keys []string, key string) int {
+	raw := cfg.OptionalCredentials(config.CredentialsPath(keys, key)...)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to parse %s: %s", key, raw))
+	}
+	return n
+}
@@ -0,0 +1,45 @@
+package discourse_crawler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+	"parser/internal/testutil"
+)
+
+const testConfig = `
+crawlers:
+  - name: ethresearch
+    type: discourse
+    credentials: [ discourse, forums, ethresearch ]
+service_providers:
+  services:
+    discourse:
+      forums:
+        ethresearch:
+          url: https://ethresear.ch/
+          source: ethresear.ch
+          min_likes: 10
+          min_replies: 5
+`
+
+func TestCrawlTopics(t *testing.T) {
+	c, _ := testutil.Crawler(t, testConfig, "ethresearch", NewCrawler, "discourse", "forums", "ethresearch")
+
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	// pinned category description and the topic below thresholds are skipped
+	require.Len(t, bodies, 1)
+
+	topic := bodies[0].ToModel().(model.Title)
+	require.Equal(t, "https://ethresear.ch/t/mev-burn-a-simple-design/101", *topic.URL)
+	require.Equal(t, model.KindCommunity, *topic.Kind)
+	require.Equal(t, "ethresear.ch", *topic.Source)
+	require.Equal(t, "We propose burning MEV at the protocol level.\n\nThe design needs no new trust assumptions.", *topic.Summary,
+		"expected opening post text without quotes")
+}
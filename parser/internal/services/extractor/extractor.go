@@ -0,0 +1,128 @@
+package extractor
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+
+	"parser/internal/config"
+)
+
+var ErrArticleNotFound = errors.New("article not found in the node tree")
+
+type Extractor interface {
+	Extract(doc *html.Node) (*html.Node, error)
+}
+
+type Registry interface {
+	// Extractor returns extractor registered for the host (or any of its parent domains), generic one otherwise
+	Extractor(host string) Extractor
+	// Extract runs host extractor for the page, falls back to the generic one if site rule did not match
+	Extract(pageURL string, doc *html.Node) (*html.Node, error)
+}
+
+type registry struct {
+	log *logrus.Entry
+
+	extractors map[string]Extractor
+	fallback   Extractor
+}
+
+func NewRegistry(cfg config.Config) Registry {
+	extractors := make(map[string]Extractor)
+	for _, rule := range cfg.ExtractorRules() {
+		e := newSelectorExtractor(rule)
+		for _, host := range rule.Hosts {
+			extractors[strings.TrimPrefix(strings.ToLower(host), "www.")] = e
+		}
+	}
+
+	return &registry{
+		log: cfg.Logging().WithField("service", "[EXTRACTOR]"),
+
+		extractors: extractors,
+		fallback:   NewReadability(),
+	}
+}
+
+func (r registry) Extractor(host string) Extractor {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for host != "" {
+		if e, ok := r.extractors[host]; ok {
+			return e
+		}
+
+		// sub.domain.com -> domain.com
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return r.fallback
+}
+
+func (r registry) Extract(pageURL string, doc *html.Node) (*html.Node, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse page url: %s", pageURL)
+	}
+
+	e := r.Extractor(u.Hostname())
+	article, err := e.Extract(doc)
+	if err == nil {
+		return article, nil
+	}
+
+	if e == r.fallback {
+		return nil, err
+	}
+
+	r.log.WithError(err).WithField("url", pageURL).Warn("Site rule did not match, falling back to generic extractor...")
+	return r.fallback.Extract(doc)
+}
+
+type selectorExtractor struct {
+	selector cascadia.Sel
+	exclude  []cascadia.Sel
+}
+
+func newSelectorExtractor(rule config.ExtractorRule) Extractor {
+	exclude := make([]cascadia.Sel, len(rule.Exclude))
+	for i, s := range rule.Exclude {
+		exclude[i] = mustCompile(s)
+	}
+
+	return &selectorExtractor{
+		selector: mustCompile(rule.Selector),
+		exclude:  exclude,
+	}
+}
+
+func (e selectorExtractor) Extract(doc *html.Node) (*html.Node, error) {
+	article := cascadia.Query(doc, e.selector)
+	if article == nil {
+		return nil, errors.Wrapf(ErrArticleNotFound, "no match for selector: %s", e.selector.String())
+	}
+
+	for _, sel := range e.exclude {
+		for _, n := range cascadia.QueryAll(article, sel) {
+			if n.Parent != nil {
+				n.Parent.RemoveChild(n)
+			}
+		}
+	}
+	return article, nil
+}
+
+func mustCompile(selector string) cascadia.Sel {
+	sel, err := cascadia.Parse(selector)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to compile extractor selector: %s", selector))
+	}
+	return sel
+}
@@ -0,0 +1,160 @@
+package extractor
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	minParagraphLen = 25
+	// minArticleScore candidates scoring less are most likely navigation or listing blocks
+	minArticleScore = 10
+)
+
+var (
+	positiveHintRegex = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text`)
+	negativeHintRegex = regexp.MustCompile(`(?i)comment|footer|header|nav|menu|sidebar|share|social|related|promo|banner|sponsor|advert|\bads?\b|subscribe|newsletter|cookie`)
+
+	unlikelyTags = map[string]bool{
+		"script": true, "style": true, "noscript": true, "nav": true,
+		"header": true, "footer": true, "aside": true, "form": true, "iframe": true,
+	}
+	candidateTags = map[string]bool{
+		"div": true, "article": true, "section": true, "main": true, "td": true,
+	}
+)
+
+// readability is a generic extractor in the spirit of arc90 readability:
+// paragraphs vote for their ancestors by text density, the best scored ancestor is the article
+type readability struct{}
+
+func NewReadability() Extractor {
+	return readability{}
+}
+
+func (r readability) Extract(doc *html.Node) (*html.Node, error) {
+	scores := make(map[*html.Node]float64)
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && unlikelyTags[n.Data] {
+			return
+		}
+
+		if n.Type == html.ElementNode && (n.Data == "p" || n.Data == "pre") {
+			scoreParagraph(n, scores)
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var best *html.Node
+	bestScore := 0.0
+	for n, score := range scores {
+		score *= 1 - linkDensity(n)
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+
+	if best == nil || bestScore < minArticleScore {
+		return nil, ErrArticleNotFound
+	}
+	return best, nil
+}
+
+func scoreParagraph(p *html.Node, scores map[*html.Node]float64) {
+	text := strings.TrimSpace(textContent(p))
+	if len(text) < minParagraphLen {
+		return
+	}
+
+	// base point, a point per comma and a point per 100 chars (up to 3)
+	score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text)/100), 3)
+
+	parent := p.Parent
+	if parent == nil || parent.Type != html.ElementNode {
+		return
+	}
+	addScore(parent, score, scores)
+
+	if grandParent := parent.Parent; grandParent != nil && grandParent.Type == html.ElementNode {
+		addScore(grandParent, score/2, scores)
+	}
+}
+
+func addScore(n *html.Node, score float64, scores map[*html.Node]float64) {
+	if !candidateTags[n.Data] {
+		return
+	}
+	if _, ok := scores[n]; !ok {
+		scores[n] = classWeight(n)
+	}
+	scores[n] += score
+}
+
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	if n.Data == "article" || n.Data == "main" {
+		weight += 10
+	}
+	for _, attr := range n.Attr {
+		if attr.Key != "class" && attr.Key != "id" {
+			continue
+		}
+		if negativeHintRegex.MatchString(attr.Val) {
+			weight -= 25
+		}
+		if positiveHintRegex.MatchString(attr.Val) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity share of the text placed inside of the links
+func linkDensity(n *html.Node) float64 {
+	textLen := len(textContent(n))
+	if textLen == 0 {
+		return 0
+	}
+
+	linksLen := 0
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode && c.Data == "a" {
+			linksLen += len(textContent(c))
+			return
+		}
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			walk(cc)
+		}
+	}
+	walk(n)
+
+	return float64(linksLen) / float64(textLen)
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+		if c.Type == html.ElementNode && unlikelyTags[c.Data] {
+			return
+		}
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			walk(cc)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package html_listing_crawler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"common/convert"
+	"common/data/model"
+	"common/hash"
+	"parser/internal/services/crawler"
+)
+
+var _ crawler.ParsedBody = body{}
+
+type body struct {
+	title       string
+	url         string
+	summary     string
+	releaseDate *time.Time
+	source      string
+}
+
+func (b body) ToModel() any {
+	var summary *string
+	if b.summary != "" {
+		summary = &b.summary
+	}
+
+	return model.Title{
+		Title:       &b.title,
+		Summary:     summary,
+		Hash:        convert.ToPtr(hash.Hash(b.title, b.url)),
+		URL:         &b.url,
+		ReleaseDate: b.releaseDate,
+		Status:      convert.ToPtr(model.StatusPending),
+		Source:      &b.source,
+		Kind:        convert.ToPtr(model.KindNews),
+	}
+}
+
+func processReleaseDate(s, layout string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		logrus.WithError(err).WithField("release-date", s).Debug("Failed to parse listing release date...")
+		return nil
+	}
+	return convert.ToPtr(t.UTC())
+}
@@ -0,0 +1,211 @@
+package html_listing_crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+)
+
+const HTMLListing = "html_listing"
+
+const (
+	defaultLinkSelector = "a"
+	defaultDateAttr     = "datetime"
+)
+
+// HTMLListingCrawler scrapes titles from the outlet listing page (e.g. homepage or category page) by css selectors
+type HTMLListingCrawler struct {
+	log *logrus.Entry
+
+	url    *url.URL
+	source string
+
+	item    cascadia.Sel
+	title   cascadia.Sel
+	link    cascadia.Sel
+	summary cascadia.Sel
+	date    cascadia.Sel
+
+	dateAttr   string
+	dateLayout string
+
+	conn connector.Connector
+}
+
+// NewCrawler listing keys point to the listing definition, e.g. [html_listing, pages, the_block]
+func NewCrawler(cfg config.Config, conn connector.Connector, listingKeys ...string) crawler.Crawler {
+	rawURL := cfg.Credentials(config.CredentialsPath(listingKeys, "url")...)
+	listingURL, err := url.Parse(rawURL)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to parse listing url: %s", rawURL))
+	}
+
+	link := optionalSelector(cfg.OptionalCredentials(config.CredentialsPath(listingKeys, "link")...))
+	if link == nil {
+		link = mustCompile(defaultLinkSelector)
+	}
+
+	dateAttr := cfg.OptionalCredentials(config.CredentialsPath(listingKeys, "date_attr")...)
+	if dateAttr == "" {
+		dateAttr = defaultDateAttr
+	}
+
+	return &HTMLListingCrawler{
+		log: cfg.Logging().WithField("service", "[HTML-LISTING-CRAWLER]").WithField("listing", listingKeys),
+
+		url:    listingURL,
+		source: cfg.Credentials(config.CredentialsPath(listingKeys, "source")...),
+
+		item:    mustCompile(cfg.Credentials(config.CredentialsPath(listingKeys, "item")...)),
+		title:   optionalSelector(cfg.OptionalCredentials(config.CredentialsPath(listingKeys, "title")...)),
+		link:    link,
+		summary: optionalSelector(cfg.OptionalCredentials(config.CredentialsPath(listingKeys, "summary")...)),
+		date:    optionalSelector(cfg.OptionalCredentials(config.CredentialsPath(listingKeys, "date")...)),
+
+		dateAttr:   dateAttr,
+		dateLayout: cfg.OptionalCredentials(config.CredentialsPath(listingKeys, "date_layout")...),
+
+		conn: conn,
+	}
+}
+
+func (c HTMLListingCrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	pageBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:         c.url.String(),
+		Conditional: true,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to poll listing %s", c.url)
+	}
+
+	// nothing changed since the last crawl
+	if statusCode == http.StatusNotModified {
+		c.log.Debug("Not modified since the last crawl...")
+		return nil, http.StatusOK, nil
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCode, nil
+	}
+
+	doc, err := html.Parse(pageBody)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to parse listing body")
+	}
+
+	items := cascadia.QueryAll(doc, c.item)
+	bodies := make([]crawler.ParsedBody, 0, len(items))
+	for _, item := range items {
+		b, ok := c.parseItem(item)
+		if !ok {
+			continue
+		}
+		bodies = append(bodies, b)
+	}
+
+	c.log.Debugf("Parsed %d of %d listing items", len(bodies), len(items))
+	return bodies, statusCode, nil
+}
+
+func (c HTMLListingCrawler) parseItem(item *html.Node) (body, bool) {
+	// items are often the links themselves: <a class="post-card">
+	linkNode := item
+	if !c.link.Match(item) {
+		linkNode = cascadia.Query(item, c.link)
+	}
+	if linkNode == nil {
+		return body{}, false
+	}
+
+	href, err := c.url.Parse(attr(linkNode, "href"))
+	if err != nil || href.Host == "" {
+		return body{}, false
+	}
+	href.Fragment = ""
+
+	titleNode := linkNode
+	if c.title != nil {
+		if n := cascadia.Query(item, c.title); n != nil {
+			titleNode = n
+		}
+	}
+
+	title := text(titleNode)
+	if title == "" {
+		return body{}, false
+	}
+
+	b := body{
+		title:  title,
+		url:    href.String(),
+		source: c.source,
+	}
+
+	if c.summary != nil {
+		if n := cascadia.Query(item, c.summary); n != nil {
+			b.summary = text(n)
+		}
+	}
+
+	if c.date != nil {
+		if n := cascadia.Query(item, c.date); n != nil {
+			rawDate := attr(n, c.dateAttr)
+			if rawDate == "" {
+				rawDate = text(n)
+			}
+			b.releaseDate = processReleaseDate(rawDate, c.dateLayout)
+		}
+	}
+
+	return b, true
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+func text(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+			sb.WriteString(" ")
+		}
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			walk(cc)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func optionalSelector(selector string) cascadia.Sel {
+	if selector == "" {
+		return nil
+	}
+	return mustCompile(selector)
+}
+
+func mustCompile(selector string) cascadia.Sel {
+	sel, err := cascadia.Parse(selector)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to compile listing selector: %s", selector))
+	}
+	return sel
+}
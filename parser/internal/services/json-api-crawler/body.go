@@ -0,0 +1,63 @@
+package json_api_crawler
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"common/convert"
+	"common/data/model"
+	"common/hash"
+	"parser/internal/services/crawler"
+)
+
+var _ crawler.ParsedBody = body{}
+
+// unixLayout date layout value for epoch seconds timestamps
+const unixLayout = "unix"
+
+type body struct {
+	title       string
+	url         string
+	summary     string
+	releaseDate *time.Time
+	source      string
+}
+
+func (b body) ToModel() any {
+	var summary *string
+	if b.summary != "" {
+		summary = &b.summary
+	}
+
+	return model.Title{
+		Title:       &b.title,
+		Summary:     summary,
+		Hash:        convert.ToPtr(hash.Hash(b.title, b.url)),
+		URL:         &b.url,
+		ReleaseDate: b.releaseDate,
+		Status:      convert.ToPtr(model.StatusPending),
+		Source:      &b.source,
+		Kind:        convert.ToPtr(model.KindNews),
+	}
+}
+
+func processReleaseDate(v any, layout string) *time.Time {
+	switch d := v.(type) {
+	case float64:
+		if layout == unixLayout {
+			return convert.ToPtr(time.Unix(int64(d), 0).UTC())
+		}
+	case string:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, d)
+		if err != nil {
+			logrus.WithError(err).WithField("release-date", d).Debug("Failed to parse api release date...")
+			return nil
+		}
+		return convert.ToPtr(t.UTC())
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package json_api_crawler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+)
+
+const JSONAPI = "json_api"
+
+// JSONAPICrawler maps items of an arbitrary JSON API response into titles by dot separated field paths
+type JSONAPICrawler struct {
+	log *logrus.Entry
+
+	url    string
+	path   string
+	source string
+
+	authHeader string
+	authToken  string
+
+	itemsPath string
+	fields    fields
+
+	conn connector.Connector
+}
+
+type fields struct {
+	title       string
+	url         string
+	summary     string
+	releaseDate string
+	dateLayout  string
+}
+
+// NewCrawler api keys point to the api definition, e.g. [json_api, apis, the_block]
+func NewCrawler(cfg config.Config, conn connector.Connector, apiKeys ...string) crawler.Crawler {
+	return &JSONAPICrawler{
+		log: cfg.Logging().WithField("service", "[JSON-API-CRAWLER]").WithField("api", apiKeys),
+
+		url:    cfg.Credentials(config.CredentialsPath(apiKeys, "url")...),
+		path:   cfg.OptionalCredentials(config.CredentialsPath(apiKeys, "path")...),
+		source: cfg.Credentials(config.CredentialsPath(apiKeys, "source")...),
+
+		authHeader: cfg.OptionalCredentials(config.CredentialsPath(apiKeys, "auth_header")...),
+		authToken:  cfg.OptionalCredentials(config.CredentialsPath(apiKeys, "auth_token")...),
+
+		itemsPath: cfg.OptionalCredentials(config.CredentialsPath(apiKeys, "items")...),
+		fields: fields{
+			title:       cfg.Credentials(config.CredentialsPath(apiKeys, "fields", "title")...),
+			url:         cfg.Credentials(config.CredentialsPath(apiKeys, "fields", "url")...),
+			summary:     cfg.OptionalCredentials(config.CredentialsPath(apiKeys, "fields", "summary")...),
+			releaseDate: cfg.OptionalCredentials(config.CredentialsPath(apiKeys, "fields", "release_date")...),
+			dateLayout:  cfg.OptionalCredentials(config.CredentialsPath(apiKeys, "fields", "date_layout")...),
+		},
+
+		conn: conn,
+	}
+}
+
+func (c JSONAPICrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	headers := http.Header{}
+	if c.authHeader != "" {
+		headers.Set(c.authHeader, c.authToken)
+	}
+
+	rawRespBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:     c.url,
+		Path:    c.path,
+		Headers: headers,
+		// authenticated calls are governed by the API terms rather than robots.txt
+		SkipRobots:  c.authHeader != "",
+		Conditional: true,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to poll api %s%s", c.url, c.path)
+	}
+
+	// nothing changed since the last crawl
+	if statusCode == http.StatusNotModified {
+		c.log.Debug("Not modified since the last crawl...")
+		return nil, http.StatusOK, nil
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCode, nil
+	}
+
+	var respBody any
+	if err := json.NewDecoder(rawRespBody).Decode(&respBody); err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to decode api response body")
+	}
+
+	items, ok := lookup(respBody, c.itemsPath).([]any)
+	if !ok {
+		return nil, statusCode, errors.Errorf("items path %q does not point to a list", c.itemsPath)
+	}
+
+	bodies := make([]crawler.ParsedBody, 0, len(items))
+	for _, item := range items {
+		b := body{
+			title:       lookupString(item, c.fields.title),
+			url:         lookupString(item, c.fields.url),
+			summary:     lookupString(item, c.fields.summary),
+			releaseDate: processReleaseDate(lookup(item, c.fields.releaseDate), c.fields.dateLayout),
+			source:      c.source,
+		}
+		if b.title == "" || b.url == "" {
+			continue
+		}
+		bodies = append(bodies, b)
+	}
+
+	c.log.Debugf("Parsed %d of %d api items", len(bodies), len(items))
+	return bodies, statusCode, nil
+}
+
+// lookup walks dot separated path, e.g. "data.posts", empty path returns the value itself
+func lookup(v any, path string) any {
+	if path == "" {
+		return v
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func lookupString(v any, path string) string {
+	if path == "" {
+		return ""
+	}
+	s, _ := lookup(v, path).(string)
+	return strings.TrimSpace(s)
+}
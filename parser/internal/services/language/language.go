@@ -0,0 +1,41 @@
+package language
+
+import (
+	"github.com/abadojack/whatlanggo"
+
+	"common/data/model"
+)
+
+// minTextLength shorter texts are too ambiguous to be detected reliably
+const minTextLength = 100
+
+// Detect ISO 639-1 code of the text language, model.LanguageUndetermined if detection is not reliable
+func Detect(text string) string {
+	if len(text) < minTextLength {
+		return model.LanguageUndetermined
+	}
+
+	info := whatlanggo.Detect(text)
+	if !info.IsReliable() {
+		return model.LanguageUndetermined
+	}
+
+	code := info.Lang.Iso6391()
+	if code == "" {
+		return model.LanguageUndetermined
+	}
+	return code
+}
+
+// Accepted undetermined languages are accepted, so that short or mixed articles are not lost
+func Accepted(code string, accepted []string) bool {
+	if len(accepted) == 0 || code == "" || code == model.LanguageUndetermined {
+		return true
+	}
+	for _, a := range accepted {
+		if a == code {
+			return true
+		}
+	}
+	return false
+}
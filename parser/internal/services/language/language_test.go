@@ -0,0 +1,28 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+)
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		"en":                       "The largest cryptocurrency by market value gained as much as five percent before paring some of the advance, while ether and other major tokens followed it higher.",
+		"de":                       "Die größte Kryptowährung nach Marktwert legte zeitweise um fünf Prozent zu, bevor sie einen Teil der Gewinne wieder abgab, während Ether und andere große Token folgten.",
+		"es":                       "La mayor criptomoneda por valor de mercado llegó a subir un cinco por ciento antes de recortar parte del avance, mientras que ether y otros tokens importantes la siguieron.",
+		model.LanguageUndetermined: "Bitcoin ETF",
+	}
+
+	for want, text := range cases {
+		require.Equal(t, want, Detect(text), text)
+	}
+}
+
+func TestAccepted(t *testing.T) {
+	require.True(t, Accepted("de", nil), "expected any language to be accepted without restrictions")
+	require.False(t, Accepted("de", []string{"en"}), "expected not listed language to be rejected")
+	require.True(t, Accepted(model.LanguageUndetermined, []string{"en"}), "expected undetermined language to be accepted")
+}
@@ -0,0 +1,292 @@
+package metadata
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Metadata page level information published by the site for link previews and search engines
+type Metadata struct {
+	// Images best first: og:image, JSON-LD article images, twitter:image
+	Images       []string
+	Author       string
+	CanonicalURL string
+	// PublishedAt in UTC: JSON-LD datePublished, article:published_time or <time datetime> of the article
+	PublishedAt *time.Time
+}
+
+// timestampLayouts ISO 8601 variations seen in the wild, dates without zone are treated as UTC
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// articleTypes JSON-LD types describing the article itself
+var articleTypes = map[string]bool{
+	"NewsArticle":         true,
+	"Article":             true,
+	"ReportageNews":       true,
+	"BlogPosting":         true,
+	"AnalysisNewsArticle": true,
+}
+
+// Extract reads OpenGraph, Twitter card and JSON-LD metadata of the page, relative urls are resolved against pageURL
+func Extract(pageURL string, doc *html.Node) Metadata {
+	base, _ := url.Parse(pageURL)
+
+	var (
+		meta           = make(map[string][]string)
+		canonical      string
+		jsonLDArticles []map[string]any
+		// published marks datetime of the article itself, others might belong to comments or related posts,
+		// so only the ones of the article body are taken, nested articles are comments most of the time
+		publishedTimes []string
+		articleTimes   []string
+	)
+
+	var walk func(n *html.Node, articleDepth int)
+	walk = func(n *html.Node, articleDepth int) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Meta:
+				key := attr(n, "property")
+				if key == "" {
+					key = attr(n, "name")
+				}
+				if key != "" {
+					key = strings.ToLower(key)
+					meta[key] = append(meta[key], strings.TrimSpace(attr(n, "content")))
+				}
+			case atom.Link:
+				if canonical == "" && hasToken(attr(n, "rel"), "canonical") {
+					canonical = attr(n, "href")
+				}
+			case atom.Time:
+				if datetime := attr(n, "datetime"); datetime != "" {
+					switch {
+					case attr(n, "itemprop") == "datePublished" || hasAttr(n, "pubdate"):
+						publishedTimes = append(publishedTimes, datetime)
+					case articleDepth == 1:
+						articleTimes = append(articleTimes, datetime)
+					}
+				}
+			case atom.Article:
+				articleDepth++
+			case atom.Script:
+				if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") && n.FirstChild != nil {
+					jsonLDArticles = append(jsonLDArticles, parseJSONLD(n.FirstChild.Data)...)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, articleDepth)
+		}
+	}
+	walk(doc, 0)
+
+	var images []string
+	images = append(images, meta["og:image:secure_url"]...)
+	images = append(images, meta["og:image"]...)
+	images = append(images, meta["og:image:url"]...)
+	for _, a := range jsonLDArticles {
+		images = append(images, jsonLDStrings(a["image"], "url")...)
+	}
+	images = append(images, meta["twitter:image"]...)
+	images = append(images, meta["twitter:image:src"]...)
+
+	res := Metadata{
+		Images: resolveUnique(base, images),
+	}
+
+	for _, a := range jsonLDArticles {
+		if authors := jsonLDStrings(a["author"], "name"); len(authors) > 0 {
+			res.Author = strings.Join(authors, ", ")
+			break
+		}
+	}
+	if res.Author == "" {
+		res.Author = first(meta["author"], meta["article:author"], meta["twitter:creator"])
+	}
+
+	var jsonLDPublished []string
+	for _, a := range jsonLDArticles {
+		jsonLDPublished = append(jsonLDPublished, jsonLDStrings(a["datePublished"], "@value")...)
+	}
+	res.PublishedAt = firstTimestamp(
+		jsonLDPublished,
+		meta["article:published_time"],
+		meta["og:published_time"],
+		meta["datepublished"],
+		publishedTimes,
+		articleTimes,
+	)
+
+	if canonical == "" {
+		canonical = first(meta["og:url"])
+	}
+	if canonical != "" {
+		res.CanonicalURL = resolve(base, canonical)
+	}
+	return res
+}
+
+// parseJSONLD collects article objects from the JSON-LD document, including the ones nested into @graph
+func parseJSONLD(raw string) []map[string]any {
+	var doc any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &doc); err != nil {
+		return nil
+	}
+
+	var articles []map[string]any
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			if isArticle(v["@type"]) {
+				articles = append(articles, v)
+			}
+			if graph, ok := v["@graph"]; ok {
+				walk(graph)
+			}
+		}
+	}
+	walk(doc)
+	return articles
+}
+
+func isArticle(t any) bool {
+	switch t := t.(type) {
+	case string:
+		return articleTypes[t]
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && articleTypes[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDStrings flattens JSON-LD value that might be a string, an object or a list of them,
+// objects are read by the given key (e.g. ImageObject.url, Person.name)
+func jsonLDStrings(v any, key string) []string {
+	switch v := v.(type) {
+	case string:
+		if s := strings.TrimSpace(v); s != "" {
+			return []string{s}
+		}
+	case map[string]any:
+		return jsonLDStrings(v[key], key)
+	case []any:
+		var res []string
+		for _, item := range v {
+			res = append(res, jsonLDStrings(item, key)...)
+		}
+		return res
+	}
+	return nil
+}
+
+func resolveUnique(base *url.URL, urls []string) []string {
+	seen := make(map[string]bool, len(urls))
+	res := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		u = resolve(base, u)
+		// data uris and other schemes can't be posted by url
+		if seen[u] || !(strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://")) {
+			continue
+		}
+		seen[u] = true
+		res = append(res, u)
+	}
+	return res
+}
+
+func resolve(base *url.URL, ref string) string {
+	parsed, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || base == nil {
+		return ref
+	}
+	return base.ResolveReference(parsed).String()
+}
+
+// firstTimestamp the first value that parses, in UTC
+func firstTimestamp(values ...[]string) *time.Time {
+	for _, vs := range values {
+		for _, v := range vs {
+			if t, ok := ParseTimestamp(v); ok {
+				return &t
+			}
+		}
+	}
+	return nil
+}
+
+// ParseTimestamp parses ISO 8601 timestamp as published by sites, result is in UTC
+func ParseTimestamp(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func first(values ...[]string) string {
+	for _, vs := range values {
+		for _, v := range vs {
+			if v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+func hasToken(list, token string) bool {
+	for _, t := range strings.Fields(list) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"common/convert"
+)
+
+func parseFixture(t *testing.T, name string) *html.Node {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer f.Close()
+
+	doc, err := html.Parse(f)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestExtract(t *testing.T) {
+	meta := Extract("https://cointelegraph.com/news/bitcoin-etf-decision-delayed-again?utm_source=rss", parseFixture(t, "article.html"))
+
+	// og first, then the images of the JSON-LD article, twitter last, duplicates are dropped
+	require.Equal(t, []string{
+		"https://images.cointelegraph.com/og-secure.jpg",
+		"https://images.cointelegraph.com/og.jpg",
+		"https://images.cointelegraph.com/ld-1.jpg",
+		"https://images.cointelegraph.com/ld-2.jpg",
+		"https://cointelegraph.com/images/twitter.jpg",
+	}, meta.Images)
+
+	// authors of the article in the @graph, not of the web page
+	require.Equal(t, "Jane Doe, John Roe", meta.Author)
+
+	// canonical link wins over og:url and is resolved against the page
+	require.Equal(t, "https://cointelegraph.com/news/bitcoin-etf-decision-delayed-again", meta.CanonicalURL)
+
+	// JSON-LD datePublished wins over article:published_time
+	require.NotNil(t, meta.PublishedAt)
+	require.Equal(t, time.Date(2023, time.October, 17, 8, 30, 0, 0, time.UTC), *meta.PublishedAt)
+}
+
+func TestExtractMetaFallbacks(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><head>
+<meta property="og:url" content="https://decrypt.co/201234/bitcoin">
+<meta name="author" content="Meta Author">
+<meta property="article:published_time" content="2023-10-17T10:00:00+02:00">
+</head><body></body></html>`))
+	require.NoError(t, err)
+
+	meta := Extract("https://decrypt.co/201234/bitcoin?ref=feed", doc)
+	require.Empty(t, meta.Images)
+	require.Equal(t, "Meta Author", meta.Author)
+	require.Equal(t, "https://decrypt.co/201234/bitcoin", meta.CanonicalURL)
+	require.NotNil(t, meta.PublishedAt)
+	require.Equal(t, time.Date(2023, time.October, 17, 8, 0, 0, 0, time.UTC), *meta.PublishedAt)
+}
+
+func TestExtractArticleTime(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want *time.Time
+	}{
+		{
+			name: "time of the article",
+			body: `<article><time datetime="2023-10-17T08:30:00Z">Oct 17</time></article>`,
+			want: convert.ToPtr(time.Date(2023, time.October, 17, 8, 30, 0, 0, time.UTC)),
+		},
+		{
+			name: "published time wins anywhere",
+			body: `<aside><time pubdate datetime="2023-10-16T00:00:00Z"></time></aside><article><time datetime="2023-10-17T08:30:00Z"></time></article>`,
+			want: convert.ToPtr(time.Date(2023, time.October, 16, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "related posts are out of the article",
+			body: `<article><p>text</p></article><aside><time datetime="2023-10-01T00:00:00Z"></time></aside>`,
+		},
+		{
+			name: "comments are nested articles",
+			body: `<article><p>text</p><section><article><time datetime="2023-10-18T00:00:00Z"></time></article></section></article>`,
+		},
+	}
+
+	for _, c := range cases {
+		doc, err := html.Parse(strings.NewReader("<html><body>" + c.body + "</body></html>"))
+		require.NoError(t, err)
+
+		require.Equal(t, c.want, Extract("https://example.com/news", doc).PublishedAt, c.name)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2023, time.October, 17, 8, 30, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"2023-10-17T08:30:00Z":            want,
+		"2023-10-17T08:30:00.123Z":        want.Add(123 * time.Millisecond),
+		"2023-10-17T10:30:00+02:00":       want,
+		"2023-10-17T10:30:00+0200":        want,
+		"2023-10-17T10:30+02:00":          want,
+		"2023-10-17T08:30:00":             want,
+		"2023-10-17 10:30:00+02:00":       want,
+		"2023-10-17 08:30:00":             want,
+		"2023-10-17":                      time.Date(2023, time.October, 17, 0, 0, 0, 0, time.UTC),
+		"Tue, 17 Oct 2023 10:30:00 +0200": want,
+		"Tue, 17 Oct 2023 08:30:00 UTC":   want,
+		"  2023-10-17T08:30:00Z\n":        want,
+	}
+
+	for raw, expected := range cases {
+		parsed, ok := ParseTimestamp(raw)
+		require.True(t, ok, "failed to parse %q", raw)
+		require.Equal(t, expected, parsed, raw)
+		require.Equal(t, time.UTC, parsed.Location(), raw)
+	}
+
+	for _, raw := range []string{"", "yesterday", "17.10.2023", "2023-13-01"} {
+		_, ok := ParseTimestamp(raw)
+		require.False(t, ok, "expected %q not to parse", raw)
+	}
+}
@@ -0,0 +1,133 @@
+package normalizer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+
+	"parser/internal/config"
+)
+
+// maxBoilerplateLen longer paragraphs are article text even if they start like boilerplate
+const maxBoilerplateLen = 300
+
+var (
+	// skippedTags never carry article text, figures only have image captions and credits
+	skippedTags = map[string]bool{
+		"script": true, "style": true, "noscript": true, "template": true, "svg": true,
+		"aside": true, "figure": true, "figcaption": true, "nav": true, "footer": true,
+		"form": true, "button": true, "iframe": true, "select": true,
+	}
+	blockTags = map[string]bool{
+		"p": true, "div": true, "section": true, "article": true, "main": true, "header": true,
+		"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"ul": true, "ol": true, "li": true, "dl": true, "dt": true, "dd": true,
+		"blockquote": true, "pre": true, "table": true, "tr": true, "br": true, "hr": true,
+	}
+
+	defaultBoilerplate = []string{
+		`(read|see) (more|also|next)(:.*|\.{3}|…)?`,
+		`(also read|related( articles| news)?|recommended( for you)?|more on this|more from \S+)(:.*)?`,
+		`(share|tweet) (this|on)\b.*`,
+		`follow us on\b.*`,
+		`(subscribe|sign up)\b.*\bnewsletter\b.*`,
+		`(click|tap) here\b.*`,
+		`(advertisement|sponsored|sponsored content|ad)`,
+		`(image|photo|photo credit|credit|source)s?:.*`,
+		`disclaimer:.*`,
+		`(copyright|©).*`,
+		`all rights reserved\.?`,
+	}
+)
+
+// Normalizer turns the extracted article into plain text with paragraphs separated by blank lines
+type Normalizer interface {
+	Text(article *html.Node) string
+}
+
+type normalizer struct {
+	boilerplate []*regexp.Regexp
+}
+
+func New(cfg config.Config) Normalizer {
+	patterns := append(append([]string(nil), defaultBoilerplate...), cfg.BoilerplatePatterns()...)
+
+	boilerplate := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		// the whole paragraph has to match, so that article text mentioning the phrase is kept
+		r, err := regexp.Compile(`(?i)^(?:` + p + `)$`)
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to compile boilerplate pattern: %s", p))
+		}
+		boilerplate = append(boilerplate, r)
+	}
+
+	return &normalizer{
+		boilerplate: boilerplate,
+	}
+}
+
+func (n normalizer) Text(article *html.Node) string {
+	var (
+		paragraphs []string
+		buf        strings.Builder
+	)
+	flush := func() {
+		p := strings.Join(strings.Fields(buf.String()), " ")
+		buf.Reset()
+
+		if p == "" || n.isBoilerplate(p) {
+			return
+		}
+		// the same sentence is often repeated as a lead and a pull quote
+		if len(paragraphs) > 0 && paragraphs[len(paragraphs)-1] == p {
+			return
+		}
+		paragraphs = append(paragraphs, p)
+	}
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			buf.WriteString(node.Data)
+			return
+		case html.CommentNode:
+			return
+		case html.ElementNode:
+			if skippedTags[node.Data] {
+				return
+			}
+		}
+
+		block := node.Type == html.ElementNode && blockTags[node.Data]
+		if block {
+			flush()
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if block {
+			flush()
+		}
+	}
+
+	walk(article)
+	flush()
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func (n normalizer) isBoilerplate(p string) bool {
+	if len(p) > maxBoilerplateLen {
+		return false
+	}
+	for _, r := range n.boilerplate {
+		if r.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
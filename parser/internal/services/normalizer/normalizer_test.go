@@ -0,0 +1,48 @@
+package normalizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"parser/internal/testutil"
+)
+
+const article = `<article>
+  <h1>Bitcoin ETF   decision
+      delayed again</h1>
+  <script>window.ads = [];</script>
+  <style>.share { color: red }</style>
+  <p>The SEC has once again postponed its decision on a spot Bitcoin ETF.</p>
+  <figure><img src="etf.jpg"><figcaption>Photo: Getty Images</figcaption></figure>
+  <div class="share">Share this article</div>
+  <p>Read more: Ether follows Bitcoin higher</p>
+  <aside>Subscribe to our daily newsletter</aside>
+  <p>Analysts said the delay was widely expected.<br>Bitcoin traded slightly lower.</p>
+  <p>Read more about the review process in the filing, which was widely read.</p>
+  <p>Advertisement</p>
+  <p>Price prediction: not financial advice.</p>
+</article>`
+
+func TestText(t *testing.T) {
+	cfg := testutil.Config(t, `
+normalizer:
+  boilerplate: [ 'price prediction:.*' ]
+`)
+
+	doc, err := html.Parse(strings.NewReader(article))
+	require.NoError(t, err)
+
+	want := strings.Join([]string{
+		"Bitcoin ETF decision delayed again",
+		"The SEC has once again postponed its decision on a spot Bitcoin ETF.",
+		"Analysts said the delay was widely expected.",
+		"Bitcoin traded slightly lower.",
+		// article text that only starts like boilerplate is kept
+		"Read more about the review process in the filing, which was widely read.",
+	}, "\n\n")
+
+	require.Equal(t, want, New(cfg).Text(doc))
+}
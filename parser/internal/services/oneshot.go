@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"common/convert"
+	"common/data"
+	"common/data/model"
+	browse_ai_crawler "parser/internal/services/browse-ai-crawler"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+	"parser/internal/services/crawler/factory"
+)
+
+func (s *service) CrawlSource(ctx context.Context, name string, persist bool) ([]model.Title, error) {
+	// dry run must not make the next crawls skip the content it has seen
+	var opts []connector.Option
+	if !persist {
+		opts = append(opts, connector.WithoutCache())
+	}
+
+	src, err := factory.ByName(s.cfg, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// tasks are created remotely and their results are delivered to the running parser, not to the dry run
+	if !persist && src.Type == browse_ai_crawler.BrowseAI {
+		return nil, errors.Errorf("crawler %s creates remote tasks, it can only be crawled with persisting", name)
+	}
+
+	crawlCtx, deferred := crawler.Defer(ctx)
+	bodies, statusCode, err := src.Crawl(crawlCtx)
+	if err != nil {
+		deferred.Rollback(ctx)
+		return nil, errors.Wrapf(err, "failed to crawl %s", name)
+	}
+	if statusCode != http.StatusOK {
+		deferred.Rollback(ctx)
+		return nil, errors.Errorf("crawler %s returned unexpected status code: %d", name, statusCode)
+	}
+
+	titles := crawler.ToModelBatch[model.Title](bodies)
+	if !persist {
+		// nothing is stored by the dry run
+		deferred.Rollback(ctx)
+		return titles, nil
+	}
+
+	if err := s.storeTitles(ctx, bodies); err != nil {
+		deferred.Rollback(ctx)
+		return nil, err
+	}
+	deferred.Commit(ctx)
+	return titles, nil
+}
+
+func (s *service) ExtractURL(ctx context.Context, pageURL string, persist bool) (*model.RawNews, error) {
+	title := model.Title{URL: &pageURL}
+
+	stored, err := s.dataProvider.TitlesProvider().ByURLs([]string{pageURL}).Select(ctx)
+	switch {
+	case err == nil:
+		title = stored[0]
+	case !errors.Is(err, data.ErrNotFound):
+		return nil, errors.Wrap(err, "failed to get title by url")
+	case persist:
+		return nil, errors.Errorf("title of %s is not stored, crawl its source first", pageURL)
+	}
+
+	if persist {
+		if err := s.checkNotExtracted(ctx, title); err != nil {
+			return nil, err
+		}
+	}
+
+	bodies, statusCodes, errs := s.newsCrawler.Crawl(ctx, []model.Title{title})
+	if errs[0] != nil {
+		return nil, errors.Wrapf(errs[0], "failed to crawl %s", pageURL)
+	}
+	if statusCodes[0] != http.StatusOK {
+		return nil, errors.Errorf("page %s returned unexpected status code: %d", pageURL, statusCodes[0])
+	}
+
+	// read before storing, since bodies that are not stored are dropped from the batch
+	rawNews := bodies[0].ToModel().(model.RawNews)
+	if persist {
+		if err := s.storeCrawled(ctx, []model.Title{title}, bodies, statusCodes, errs); err != nil {
+			return nil, err
+		}
+	}
+	return &rawNews, nil
+}
+
+// checkNotExtracted raw news are not unique by title, extracting the title again would digest it twice
+func (s *service) checkNotExtracted(ctx context.Context, title model.Title) error {
+	if convert.FromPtr(title.Status) == model.StatusProcessed {
+		return errors.Errorf("title of %s is processed already", convert.FromPtr(title.URL))
+	}
+
+	_, err := s.dataProvider.RawNewsProvider().ByTitleIDs([]uuid.UUID{title.ID}).Select(ctx)
+	switch {
+	case err == nil:
+		return errors.Errorf("raw news of %s are stored already", convert.FromPtr(title.URL))
+	case !errors.Is(err, data.ErrNotFound):
+		return errors.Wrap(err, "failed to get raw news by title")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package reddit_crawler
+
+import (
+	"strings"
+	"time"
+
+	"common/convert"
+	"common/data/model"
+	"common/hash"
+	"parser/internal/services/crawler"
+)
+
+var _ crawler.ParsedBody = body{}
+
+// maxSummaryLen long self posts are cut, the opening is enough to tell the sentiment
+const maxSummaryLen = 4000
+
+// rawListing reddit listing, e.g. /r/CryptoCurrency/top.json
+type rawListing struct {
+	Data struct {
+		Children []struct {
+			Kind string  `json:"kind"`
+			Data rawPost `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+type rawPost struct {
+	Title       string  `json:"title"`
+	SelfText    string  `json:"selftext"`
+	URL         string  `json:"url"`
+	Permalink   string  `json:"permalink"`
+	Score       int     `json:"score"`
+	NumComments int     `json:"num_comments"`
+	CreatedUTC  float64 `json:"created_utc"`
+	IsSelf      bool    `json:"is_self"`
+	Stickied    bool    `json:"stickied"`
+	Over18      bool    `json:"over_18"`
+}
+
+type body struct {
+	title       string
+	url         string
+	summary     string
+	releaseDate *time.Time
+	source      string
+}
+
+func (b body) ToModel() any {
+	return model.Title{
+		Title:       &b.title,
+		Summary:     &b.summary,
+		Hash:        convert.ToPtr(hash.Hash(b.title, b.url)),
+		URL:         &b.url,
+		ReleaseDate: b.releaseDate,
+		Status:      convert.ToPtr(model.StatusPending),
+		Source:      &b.source,
+		Kind:        convert.ToPtr(model.KindCommunity),
+	}
+}
+
+// summary self post text, link posts only have the link they share
+func (p rawPost) summary() string {
+	text := strings.TrimSpace(p.SelfText)
+	if !p.IsSelf || text == "" {
+		text = strings.TrimSpace(p.URL)
+	}
+	if len(text) > maxSummaryLen {
+		text = strings.ToValidUTF8(text[:maxSummaryLen], "")
+	}
+	return text
+}
+
+func (p rawPost) releaseDate() *time.Time {
+	if p.CreatedUTC <= 0 {
+		return nil
+	}
+	return convert.ToPtr(time.Unix(int64(p.CreatedUTC), 0).UTC())
+}
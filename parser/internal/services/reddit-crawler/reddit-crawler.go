@@ -0,0 +1,144 @@
+package reddit_crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+)
+
+const Reddit = "reddit"
+
+const (
+	defaultURL     = "https://www.reddit.com"
+	defaultListing = "top"
+	defaultPeriod  = "day"
+	defaultLimit   = "25"
+)
+
+// RedditCrawler reads posts of the subreddit listing, posts below the score and comments thresholds are skipped
+type RedditCrawler struct {
+	log *logrus.Entry
+
+	url       string
+	subreddit string
+	listing   string
+	period    string
+	limit     string
+	source    string
+
+	minScore    int
+	minComments int
+
+	conn connector.Connector
+}
+
+// NewCrawler subreddit keys point to the subreddit definition, e.g. [reddit, subreddits, cryptocurrency]
+func NewCrawler(cfg config.Config, conn connector.Connector, subredditKeys ...string) crawler.Crawler {
+	subreddit := cfg.Credentials(config.CredentialsPath(subredditKeys, "subreddit")...)
+
+	source := cfg.OptionalCredentials(config.CredentialsPath(subredditKeys, "source")...)
+	if source == "" {
+		source = "reddit/r/" + subreddit
+	}
+
+	return &RedditCrawler{
+		log: cfg.Logging().WithField("service", "[REDDIT-CRAWLER]").WithField("subreddit", subreddit),
+
+		url:       optional(cfg, subredditKeys, "url", defaultURL),
+		subreddit: subreddit,
+		listing:   optional(cfg, subredditKeys, "listing", defaultListing),
+		period:    optional(cfg, subredditKeys, "period", defaultPeriod),
+		limit:     optional(cfg, subredditKeys, "limit", defaultLimit),
+		source:    source,
+
+		minScore:    threshold(cfg, subredditKeys, "min_score"),
+		minComments: threshold(cfg, subredditKeys, "min_comments"),
+
+		conn: conn,
+	}
+}
+
+func (c RedditCrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	rawListingBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:  c.url,
+		Path: fmt.Sprintf("/r/%s/%s.json", c.subreddit, c.listing),
+		Params: url.Values{
+			"t":     []string{c.period},
+			"limit": []string{c.limit},
+			// titles and self posts are html-escaped otherwise
+			"raw_json": []string{"1"},
+		},
+		// listing API is governed by the API terms, robots.txt of the site disallows everything
+		SkipRobots: true,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to poll reddit listing")
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCode, nil
+	}
+
+	var listing rawListing
+	if err := json.NewDecoder(rawListingBody).Decode(&listing); err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to decode listing body")
+	}
+
+	bodies := make([]crawler.ParsedBody, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		post := child.Data
+		// t3 are links, the only kind of the subreddit listing, pinned posts are announcements of the moderators
+		if child.Kind != "t3" || post.Stickied || post.Over18 {
+			continue
+		}
+		if post.Score < c.minScore || post.NumComments < c.minComments {
+			continue
+		}
+
+		title := strings.TrimSpace(post.Title)
+		if title == "" || post.Permalink == "" {
+			continue
+		}
+
+		bodies = append(bodies, body{
+			title:       title,
+			url:         strings.TrimSuffix(c.url, "/") + post.Permalink,
+			summary:     post.summary(),
+			releaseDate: post.releaseDate(),
+			source:      c.source,
+		})
+	}
+
+	c.log.Debugf("Parsed %d of %d listing posts", len(bodies), len(listing.Data.Children))
+	return bodies, statusCode, nil
+}
+
+func optional(cfg config.Config, keys []string, key, fallback string) string {
+	if v := cfg.OptionalCredentials(config.CredentialsPath(keys, key)...); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func threshold(cfg config.Config, keys []string, key string) int {
+	raw := cfg.OptionalCredentials(config.CredentialsPath(keys, key)...)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to parse %s: %s", key, raw))
+	}
+	return n
+}
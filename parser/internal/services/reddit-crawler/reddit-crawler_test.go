@@ -0,0 +1,51 @@
+package reddit_crawler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+	"parser/internal/testutil"
+)
+
+const testConfig = `
+crawlers:
+  - name: cryptocurrency
+    type: reddit
+    credentials: [ reddit, subreddits, cryptocurrency ]
+service_providers:
+  services:
+    reddit:
+      subreddits:
+        cryptocurrency:
+          subreddit: CryptoCurrency
+          min_score: 100
+          min_comments: 50
+`
+
+func TestCrawlListing(t *testing.T) {
+	c, _ := testutil.Crawler(t, testConfig, "reddit", NewCrawler, "reddit", "subreddits", "cryptocurrency")
+
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	// stickied daily thread and the post below thresholds are skipped
+	require.Len(t, bodies, 2)
+
+	self := bodies[0].ToModel().(model.Title)
+	require.Equal(t, "https://www.reddit.com/r/CryptoCurrency/comments/2/the_etf_delay_is_priced_in/", *self.URL,
+		"expected permalink")
+	require.Equal(t, "reddit/r/CryptoCurrency", *self.Source)
+	require.Equal(t, model.KindCommunity, *self.Kind)
+	require.Equal(t, "Everyone expected the SEC to push the deadline again, the market barely moved.", *self.Summary,
+		"expected self text summary")
+	require.NotNil(t, self.ReleaseDate)
+	require.EqualValues(t, 1697531400, self.ReleaseDate.Unix())
+
+	link := bodies[1].ToModel().(model.Title)
+	require.Equal(t, "https://www.coindesk.com/markets/2023/10/17/bitcoin-tops-30k-as-etf-hopes-build/", *link.Summary,
+		"expected shared link summary")
+}
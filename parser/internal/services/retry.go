@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common"
+	"common/convert"
+	"common/data/model"
+	commonmath "common/math"
+)
+
+const maxLastErrorLen = 1024
+
+// markFailed records failed attempt of the title, it is retried with exponential backoff
+// until it runs out of attempts and becomes dead
+func (s *service) markFailed(ctx context.Context, title model.Title, reason error) error {
+	params := s.failedParams(title, reason, common.CurrentTimestamp())
+	if convert.FromPtr(params.Status) == model.StatusDead {
+		s.log.WithFields(logrus.Fields{
+			"title-id":  title.ID,
+			"title-url": convert.FromPtr(title.URL),
+			"attempts":  convert.FromPtr(params.Attempts),
+		}).Warn("title ran out of attempts, marking as dead...")
+	}
+
+	if _, err := s.dataProvider.TitlesProvider().ByIDs([]uuid.UUID{title.ID}).Update(ctx, params); err != nil {
+		return errors.Wrap(err, "failed to update title attempts")
+	}
+	return nil
+}
+
+// failedParams the title is due again after the backoff, or dead once it is out of attempts
+func (s *service) failedParams(title model.Title, reason error, now time.Time) model.UpdateTitleParams {
+	attempts := convert.FromPtr(title.Attempts) + 1
+
+	lastError := reason.Error()
+	if len(lastError) > maxLastErrorLen {
+		lastError = lastError[:maxLastErrorLen]
+	}
+
+	params := model.UpdateTitleParams{
+		Status:        convert.ToPtr(model.StatusFailed),
+		Attempts:      convert.ToPtr(attempts),
+		LastError:     convert.ToPtr(lastError),
+		NextAttemptAt: convert.ToPtr(now.Add(retryBackoff(attempts, s.cfg.RetryBackoff(), s.cfg.MaxRetryBackoff()))),
+	}
+
+	if attempts >= s.cfg.MaxAttempts() {
+		params.Status = convert.ToPtr(model.StatusDead)
+		params.NextAttemptAt = nil
+	}
+	return params
+}
+
+// retryBackoff delay before the next attempt: base * 2^(attempts-1), capped by max
+func retryBackoff(attempts int, base, max time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && backoff < max; i++ {
+		backoff *= 2
+	}
+	return commonmath.MinDuration(backoff, max)
+}
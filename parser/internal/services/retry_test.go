@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"common/convert"
+	"common/data/model"
+	"parser/internal/testutil"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		// capped by the max backoff
+		{7, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.want, retryBackoff(c.attempts, time.Minute, time.Hour), "attempts: %d", c.attempts)
+	}
+}
+
+func TestFailedParams(t *testing.T) {
+	s := &service{cfg: testutil.Config(t, `
+retry:
+  max_attempts: 3
+  backoff: 1m
+  max_backoff: 1h
+`)}
+	now := time.Date(2023, time.October, 17, 12, 0, 0, 0, time.UTC)
+
+	params := s.failedParams(model.Title{Attempts: convert.ToPtr(1)}, errors.New(strings.Repeat("e", 2*maxLastErrorLen)), now)
+	require.Equal(t, model.StatusFailed, convert.FromPtr(params.Status))
+	require.Equal(t, 2, convert.FromPtr(params.Attempts))
+	require.Equal(t, now.Add(2*time.Minute), convert.FromPtr(params.NextAttemptAt))
+	require.Len(t, convert.FromPtr(params.LastError), maxLastErrorLen)
+
+	// the last attempt makes the title dead, it is never due again
+	params = s.failedParams(model.Title{Attempts: convert.ToPtr(2)}, errors.New("timeout"), now)
+	require.Equal(t, model.StatusDead, convert.FromPtr(params.Status))
+	require.Equal(t, 3, convert.FromPtr(params.Attempts))
+	require.Nil(t, params.NextAttemptAt)
+}
@@ -0,0 +1,188 @@
+package rss_crawler
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+
+	"common/convert"
+	"common/data/model"
+	"common/hash"
+	"parser/internal/services/crawler"
+)
+
+var _ crawler.ParsedBody = body{}
+
+// feedTimeLayouts lists layouts met in the wild, RSS 2.0 declares RFC822 but most feeds deviate from it
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+}
+
+var blockElements = map[string]bool{"p": true, "br": true, "div": true, "li": true}
+
+// rawFeed covers both RSS 2.0 (<rss><channel><item>) and Atom (<feed><entry>) documents
+type rawFeed struct {
+	Channel *struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	DCDate      string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type atomEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+type body struct {
+	title       string
+	url         string
+	summary     string
+	releaseDate *time.Time
+	source      string
+}
+
+func (b body) ToModel() any {
+	return model.Title{
+		Title:       &b.title,
+		Summary:     &b.summary,
+		Hash:        convert.ToPtr(hash.Hash(b.title, b.url)),
+		URL:         &b.url,
+		ReleaseDate: b.releaseDate,
+		Status:      convert.ToPtr(model.StatusPending),
+		Source:      &b.source,
+		Kind:        convert.ToPtr(model.KindNews),
+	}
+}
+
+func (f rawFeed) bodies(source string) []body {
+	bodies := make([]body, 0, len(f.Entries))
+
+	if f.Channel != nil {
+		for _, item := range f.Channel.Items {
+			link := strings.TrimSpace(item.Link)
+			if link == "" && strings.HasPrefix(item.GUID, "http") {
+				link = strings.TrimSpace(item.GUID)
+			}
+
+			releaseDate := item.PubDate
+			if releaseDate == "" {
+				releaseDate = item.DCDate
+			}
+
+			bodies = append(bodies, body{
+				title:       strings.TrimSpace(item.Title),
+				url:         link,
+				summary:     stripHTML(item.Description),
+				releaseDate: processReleaseDate(releaseDate),
+				source:      source,
+			})
+		}
+	}
+
+	for _, entry := range f.Entries {
+		summary := entry.Summary
+		if summary == "" {
+			summary = entry.Content
+		}
+
+		releaseDate := entry.Published
+		if releaseDate == "" {
+			releaseDate = entry.Updated
+		}
+
+		bodies = append(bodies, body{
+			title:       strings.TrimSpace(entry.Title),
+			url:         entry.link(),
+			summary:     stripHTML(summary),
+			releaseDate: processReleaseDate(releaseDate),
+			source:      source,
+		})
+	}
+
+	return bodies
+}
+
+// link prefers rel="alternate" (or missing rel) link, which is the article itself per Atom spec
+func (e atomEntry) link() string {
+	for _, l := range e.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	if len(e.Links) > 0 {
+		return strings.TrimSpace(e.Links[0].Href)
+	}
+	return strings.TrimSpace(e.ID)
+}
+
+func processReleaseDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	for _, layout := range feedTimeLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return convert.ToPtr(t.UTC())
+		}
+	}
+
+	logrus.WithField("release-date", s).Debug("Failed to parse feed release date...")
+	return nil
+}
+
+// stripHTML feed descriptions are often html-encoded snippets, we only keep the text
+func stripHTML(s string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(s), nil)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(s)))
+	for _, n := range nodes {
+		collectText(n, buf)
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+func collectText(n *html.Node, buf *bytes.Buffer) {
+	if n.Type == html.TextNode {
+		buf.WriteString(n.Data)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		collectText(c, buf)
+	}
+	// keep words of adjacent blocks apart: <p>one</p><p>two</p>
+	if n.Type == html.ElementNode && blockElements[n.Data] {
+		buf.WriteString(" ")
+	}
+}
@@ -0,0 +1,76 @@
+package rss_crawler
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html/charset"
+
+	"common/iteration"
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+)
+
+const RSS = "rss"
+
+type RSSCrawler struct {
+	log *logrus.Entry
+
+	url    string
+	source string
+	conn   connector.Connector
+}
+
+// NewCrawler feed keys point to the feed definition, e.g. [rss, feeds, coindesk]
+func NewCrawler(cfg config.Config, conn connector.Connector, feedKeys ...string) crawler.Crawler {
+	return &RSSCrawler{
+		log: cfg.Logging().WithField("service", "[RSS-CRAWLER]").WithField("feed", feedKeys),
+
+		url:    cfg.Credentials(config.CredentialsPath(feedKeys, "url")...),
+		source: cfg.Credentials(config.CredentialsPath(feedKeys, "source")...),
+
+		conn: conn,
+	}
+}
+
+func (c RSSCrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	feedBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:         c.url,
+		Conditional: true,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to poll feed %s", c.url)
+	}
+
+	// nothing changed since the last crawl
+	if statusCode == http.StatusNotModified {
+		c.log.Debug("Not modified since the last crawl...")
+		return nil, http.StatusOK, nil
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCode, nil
+	}
+
+	decoder := xml.NewDecoder(feedBody)
+	// plenty of feeds are still served in legacy encodings, e.g. windows-1251
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var feed rawFeed
+	if err := decoder.Decode(&feed); err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to decode feed body")
+	}
+
+	bodies := feed.bodies(c.source)
+	c.log.Debugf("Parsed %d feed entries", len(bodies))
+
+	return iteration.Map(bodies, toModel), statusCode, nil
+}
+
+func toModel(b body) crawler.ParsedBody {
+	return b
+}
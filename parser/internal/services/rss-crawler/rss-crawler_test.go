@@ -0,0 +1,52 @@
+package rss_crawler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+	"parser/internal/testutil"
+)
+
+const testConfig = `
+crawlers:
+  - name: coindesk
+    type: rss
+    credentials: [ rss, feeds, coindesk ]
+service_providers:
+  services:
+    rss:
+      feeds:
+        coindesk:
+          url: https://www.coindesk.com/arc/outboundfeeds/rss/
+          source: coindesk
+`
+
+func TestCrawlFeed(t *testing.T) {
+	c, _ := testutil.Crawler(t, testConfig, "coindesk", NewCrawler, "rss", "feeds", "coindesk")
+
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, bodies, 2)
+
+	first := bodies[0].ToModel().(model.Title)
+	require.Equal(t, "Bitcoin Tops $30K as ETF Hopes Build", *first.Title, "expected trimmed title")
+	require.Equal(t, "The largest cryptocurrency rallied for the first time since June.", *first.Summary,
+		"expected html stripped from the summary")
+	require.NotNil(t, first.ReleaseDate)
+	require.True(t, first.ReleaseDate.Equal(time.Date(2023, time.October, 17, 8, 30, 0, 0, time.UTC)),
+		"unexpected release date %s", first.ReleaseDate)
+
+	// no link, permalink guid and dc:date instead
+	second := bodies[1].ToModel().(model.Title)
+	require.Equal(t, "https://www.coindesk.com/markets/2023/10/17/ether-follows-bitcoin-higher/", *second.URL,
+		"expected guid to be used as the link")
+	require.NotNil(t, second.ReleaseDate)
+	require.Equal(t, 9, second.ReleaseDate.Hour(), "expected dc:date release date")
+	require.Equal(t, "coindesk", *second.Source)
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"time"
@@ -17,14 +18,23 @@ import (
 	"common/data/store"
 	"common/iteration"
 	"parser/internal/config"
-	browse_ai_crawler "parser/internal/services/browse-ai-crawler"
+	"parser/internal/services/connector"
 	"parser/internal/services/crawler"
+	"parser/internal/services/crawler/factory"
+	"parser/internal/services/language"
+	"parser/internal/services/stories"
 	url_crawler "parser/internal/services/url-crawler"
+	"parser/internal/services/webhooks"
 	"parser/internal/services/worker"
 )
 
 type Service interface {
 	Run(ctx context.Context) error
+
+	// CrawlSource runs the named crawler once, titles are stored only if persist is set
+	CrawlSource(ctx context.Context, name string, persist bool) ([]model.Title, error)
+	// ExtractURL crawls and extracts the single page, persisting requires the title of the url to be stored already
+	ExtractURL(ctx context.Context, pageURL string, persist bool) (*model.RawNews, error)
 }
 
 const workersNum = 1
@@ -33,157 +43,396 @@ type service struct {
 	cfg config.Config
 	log *logrus.Entry
 
-	titlesCrawlers []crawler.Crawler
-	newsCrawler    crawler.MultiCrawler[model.Title]
+	titlesSources []factory.Source
+	newsCrawler   crawler.MultiCrawler[model.Title]
+	stories       stories.Grouper
 
 	dataProvider store.DataProvider
 }
 
 func NewService(cfg config.Config) Service {
 	return &service{
-		cfg: cfg,
-		log: cfg.Logging().WithField("service", "[PARSER]"),
-		titlesCrawlers: []crawler.Crawler{
-			browse_ai_crawler.NewCrawler(cfg, cfg.Credentials(browse_ai_crawler.BrowseAI, "robots", "coin_telegraph")),
-		},
-		newsCrawler:  url_crawler.NewCrawler(cfg),
-		dataProvider: store.New(cfg),
+		cfg:           cfg,
+		log:           cfg.Logging().WithField("service", "[PARSER]"),
+		titlesSources: factory.New(cfg),
+		newsCrawler:   url_crawler.NewCrawler(cfg),
+		stories:       stories.New(cfg),
+		dataProvider:  store.New(cfg),
 	}
 }
 
 func (s *service) Run(ctx context.Context) error {
 	s.log.Infof("Staring crawling every %v...", s.cfg.CrawlEvery())
+	if len(s.titlesSources) == 0 {
+		s.log.Warn("No crawlers configured, only pending titles will be processed...")
+	}
+
+	// crawler name : next time it is due
+	nextCrawl := make(map[string]time.Time, len(s.titlesSources))
 	go func() {
 		common.RunEveryWithBackoff(s.cfg.CrawlEvery(), 15*time.Second, 15*time.Minute, func() error {
-			s.log.Debugf("Crawling %d...", len(s.titlesCrawlers))
+			dueCrawlers := s.dueCrawlers(nextCrawl, common.CurrentTimestamp())
+			if len(dueCrawlers) == 0 {
+				return nil
+			}
+
+			s.log.Debugf("Crawling %d...", len(dueCrawlers))
 
 			wrk := worker.New(workersNum, s.cfg)
 
-			wrk.Produce(ctx, s.titlesCrawlers)
+			wrk.Produce(ctx, iteration.Map(dueCrawlers, func(src factory.Source) crawler.Crawler {
+				return src.Crawler
+			}))
 			for _, t := range wrk.Work(ctx) {
-				if t.Err != nil {
-					return errors.Wrap(t.Err, "failed to crawl")
-				}
-
-				if t.StatusCode != http.StatusOK {
-					s.log.WithFields(logrus.Fields{
-						"status-code": t.StatusCode,
-						"info":        t.StatusBody,
-					}).Warn("request returned unsuccessful status code...")
-					continue
-				}
-
-				bodiesBatch := t.Body
-
-				if len(bodiesBatch) == 0 {
-					s.log.Debug("early stopping, no new titles...")
+				// a failing crawler doesn't hold back the titles of the others
+				log := s.log.WithField("crawler", dueCrawlers[t.Seq()].Name)
+				if err := s.storeTask(ctx, t); err != nil {
+					log.WithError(err).Error("failed to crawl")
+					t.Deferred.Rollback(ctx)
 					continue
 				}
-
-				titlesBatch := crawler.ToModelBatch[model.Title](bodiesBatch)
-				s.log.Debugf("Adding new batch to the database: %d", len(bodiesBatch))
-				err := s.dataProvider.TitlesProvider().InsertUniqueBatch(ctx, titlesBatch)
-				if err != nil {
-					return errors.Wrap(err, "failed to insert batch of titles")
-				}
+				t.Deferred.Commit(ctx)
 			}
 			return nil
 		})
 	}()
 
+	if s.cfg.WebhookAddr() != "" {
+		go func() {
+			if err := webhooks.New(s.cfg, s.storeTitles).Listen(ctx); err != nil {
+				s.log.WithError(err).Error("webhooks server failed")
+			}
+		}()
+	}
+
 	return common.RunEvery(s.cfg.CrawlEvery()/4, func() error {
-		// TODO: process this in batches to reduce RAM load
-		pendingTitles, err := s.dataProvider.TitlesProvider().ByStatus(model.StatusPending, model.StatusFailed).Select(ctx)
+		return s.processPendingTitles(ctx)
+	})
+}
+
+// storeTask stores titles of the finished crawl task, unsuccessful status code is not an error,
+// the crawl is just given up until the next time
+func (s *service) storeTask(ctx context.Context, t worker.Task) error {
+	if t.Err != nil {
+		return t.Err
+	}
+
+	if t.StatusCode != http.StatusOK {
+		s.log.WithFields(logrus.Fields{
+			"status-code": t.StatusCode,
+			"info":        t.StatusBody,
+		}).Warn("request returned unsuccessful status code...")
+		return nil
+	}
+
+	return s.storeTitles(ctx, t.Body)
+}
+
+// storeTitles inserts new titles, crawled or received by webhooks
+func (s *service) storeTitles(ctx context.Context, bodiesBatch []crawler.ParsedBody) error {
+	if len(bodiesBatch) == 0 {
+		s.log.Debug("early stopping, no new titles...")
+		return nil
+	}
+
+	titlesBatch := crawler.ToModelBatch[model.Title](bodiesBatch)
+	s.log.Debugf("Adding new batch to the database: %d", len(bodiesBatch))
+	err := s.dataProvider.TitlesProvider().InsertUniqueBatch(ctx, titlesBatch)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert batch of titles")
+	}
+
+	if err := s.linkTitlesCoins(ctx, titlesBatch); err != nil {
+		return errors.Wrap(err, "failed to link titles coins")
+	}
+	return nil
+}
+
+// processPendingTitles walks due titles in keyset order, every batch is claimed with SKIP LOCKED,
+// so several parser replicas never process the same title twice
+func (s *service) processPendingTitles(ctx context.Context) error {
+	now := common.CurrentTimestamp()
+
+	var lastID *uuid.UUID
+	for {
+		// processing titles are claimed again once the lease of the replica that claimed them expires
+		provider := s.dataProvider.TitlesProvider().
+			ByStatus(model.StatusPending, model.StatusFailed, model.StatusProcessing).
+			DueAt(now)
+		if lastID != nil {
+			provider = provider.After(*lastID)
+		}
+
+		pendingTitles, err := provider.Claim(ctx, s.cfg.BatchSize(), common.CurrentTimestamp().Add(s.cfg.ClaimLease()))
 		if err != nil {
 			if !errors.Is(err, data.ErrNotFound) {
-				return errors.Wrap(err, "failed to select pending titles")
+				return errors.Wrap(err, "failed to claim pending titles")
 			}
 
 			s.log.Debug("Have not found pending titles records")
 			return nil
 		}
 
-		body, statusCodes, errs := s.newsCrawler.Crawl(ctx, pendingTitles)
-		if err != nil {
-			if !errors.Is(err, data.ErrNotFound) {
-				return errors.Wrap(err, "failed to crawl url")
-			}
+		s.log.Debugf("Claimed batch of titles: %d", len(pendingTitles))
+		if err := s.processTitles(ctx, pendingTitles); err != nil {
+			return errors.Wrap(err, "failed to process batch of titles")
+		}
+
+		if uint64(len(pendingTitles)) < s.cfg.BatchSize() {
 			return nil
 		}
+		lastID = convert.ToPtr(maxID(pendingTitles))
+	}
+}
 
-		failedIDs := set.NewSet[uuid.UUID]()
-		for i, statusCode := range statusCodes {
-			if statusCode != http.StatusOK {
-				s.log.WithFields(logrus.Fields{
-					"title-id":    pendingTitles[i].ID,
-					"title-url":   convert.FromPtr(pendingTitles[i].URL),
-					"status-code": statusCode,
-					"info":        body,
-				}).Warn("request returned unsuccessful status code...")
+func (s *service) processTitles(ctx context.Context, pendingTitles []model.Title) error {
+	body, statusCodes, errs := s.newsCrawler.Crawl(ctx, pendingTitles)
+	return s.storeCrawled(ctx, pendingTitles, body, statusCodes, errs)
+}
 
-				failedIDs.Put(pendingTitles[i].ID)
-			}
+// storeCrawled stores raw news of the crawled titles and moves titles along, results are index-aligned with titles
+func (s *service) storeCrawled(ctx context.Context, pendingTitles []model.Title, body []crawler.ParsedBody, statusCodes []int, errs []error) error {
+	// title index : reason of the failure
+	failed := make(map[int]error)
+	for i, statusCode := range statusCodes {
+		// failed requests have no status code, they are sorted out by their errors below
+		if errs[i] != nil {
+			continue
 		}
+		if statusCode != http.StatusOK {
+			s.log.WithFields(logrus.Fields{
+				"title-id":    pendingTitles[i].ID,
+				"title-url":   convert.FromPtr(pendingTitles[i].URL),
+				"status-code": statusCode,
+			}).Warn("request returned unsuccessful status code...")
 
-		for i, err := range errs {
-			if err != nil {
-				s.log.WithFields(logrus.Fields{
-					"title-id":  pendingTitles[i].ID,
-					"title-url": convert.FromPtr(pendingTitles[i].URL),
-					"info":      body,
-				}).WithError(err).Error("failed to run request...")
+			failed[i] = errors.Errorf("unexpected status code: %d", statusCode)
+		}
+	}
+
+	disallowedIDs := set.NewSet[uuid.UUID]()
+	for i, err := range errs {
+		if err != nil {
+			if errors.Is(err, connector.ErrDisallowed) {
+				s.log.WithField("title-url", convert.FromPtr(pendingTitles[i].URL)).Info("url is disallowed by robots.txt...")
 
-				failedIDs.Put(pendingTitles[i].ID)
+				disallowedIDs.Put(pendingTitles[i].ID)
+				continue
 			}
+
+			s.log.WithFields(logrus.Fields{
+				"title-id":  pendingTitles[i].ID,
+				"title-url": convert.FromPtr(pendingTitles[i].URL),
+			}).WithError(err).Error("failed to run request...")
+
+			failed[i] = err
 		}
+	}
 
-		successIDs := set.NewSet[uuid.UUID]()
-		for i := range pendingTitles {
-			if errs[i] == nil && statusCodes[i] == http.StatusOK {
-				successIDs.Put(pendingTitles[i].ID)
-			}
+	successIDs := set.NewSet[uuid.UUID]()
+	rejectedIDs := set.NewSet[uuid.UUID]()
+	for i := range pendingTitles {
+		if errs[i] != nil || statusCodes[i] != http.StatusOK {
+			continue
 		}
 
-		if len(body) == 0 {
-			s.log.Debug("early stopping, no new titles...")
-			return nil
+		if !s.languageAccepted(pendingTitles[i], body[i]) {
+			rejectedIDs.Put(pendingTitles[i].ID)
+			body[i] = nil
+			continue
 		}
+		successIDs.Put(pendingTitles[i].ID)
+	}
 
-		rawNewsBatch := crawler.ToModelBatch[model.RawNews](body)
-		s.log.Debugf("Adding new batch to the database: %d", len(body))
-		err = s.dataProvider.RawNewsProvider().InsertBatch(ctx, rawNewsBatch)
-		if err != nil {
-			return errors.Wrap(err, "failed to insert batch of titles")
+	for i, reason := range failed {
+		if err := s.markFailed(ctx, pendingTitles[i], reason); err != nil {
+			return errors.Wrap(err, "failed to mark title as failed")
 		}
+	}
 
-		err = s.updateStatusForProcessed(ctx, mapFilter(successIDs, rawNewsBatch), model.StatusProcessed)
-		if err != nil {
-			return errors.Wrap(err, "failed to update titles status to processed")
+	err := s.updateStatusForProcessed(ctx, setValues(disallowedIDs), model.StatusDisallowed)
+	if err != nil {
+		return errors.Wrap(err, "failed to update titles status to disallowed")
+	}
+
+	err = s.updateStatusForProcessed(ctx, setValues(rejectedIDs), model.StatusRejected)
+	if err != nil {
+		return errors.Wrap(err, "failed to update titles status to rejected")
+	}
+
+	// bodies are index-aligned with titles, failed ones are nil
+	parsedBodies := iteration.Filter(body, func(b crawler.ParsedBody) bool {
+		return b != nil
+	})
+
+	if len(parsedBodies) == 0 {
+		s.log.Debug("early stopping, no new titles...")
+		return nil
+	}
+
+	rawNewsBatch := crawler.ToModelBatch[model.RawNews](parsedBodies)
+	if err := s.resolveReleaseDates(ctx, rawNewsBatch); err != nil {
+		return errors.Wrap(err, "failed to resolve titles release dates")
+	}
+
+	// near-duplicates of already known stories are linked to them without a body of their own
+	rawNewsBatch, err = s.stories.Group(ctx, pendingTitles, rawNewsBatch)
+	if err != nil {
+		return errors.Wrap(err, "failed to group titles into stories")
+	}
+
+	s.log.Debugf("Adding new batch to the database: %d", len(rawNewsBatch))
+	err = s.dataProvider.RawNewsProvider().InsertBatch(ctx, rawNewsBatch)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert batch of titles")
+	}
+
+	err = s.updateStatusForProcessed(ctx, setValues(successIDs), model.StatusProcessed)
+	if err != nil {
+		return errors.Wrap(err, "failed to update titles status to processed")
+	}
+
+	return nil
+}
+
+// languageAccepted articles in languages not accepted from the title source are not summarized
+func (s *service) languageAccepted(t model.Title, b crawler.ParsedBody) bool {
+	rawNews, ok := b.ToModel().(model.RawNews)
+	if !ok {
+		return true
+	}
+
+	lang := convert.FromPtr(rawNews.Language)
+	if language.Accepted(lang, s.cfg.AcceptedLanguages(convert.FromPtr(t.Source))) {
+		return true
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"title-url": convert.FromPtr(t.URL),
+		"language":  lang,
+	}).Info("article language is not accepted from the source...")
+	return false
+}
+
+// maxID the last title in keyset order, uuids are compared the same way as in postgres
+func maxID(titles []model.Title) uuid.UUID {
+	var id uuid.UUID
+	for _, t := range titles {
+		if bytes.Compare(t.ID[:], id[:]) > 0 {
+			id = t.ID
 		}
+	}
+	return id
+}
 
-		err = s.updateStatusForProcessed(ctx, mapFilter(failedIDs, rawNewsBatch), model.StatusFailed)
-		if err != nil {
-			return errors.Wrap(err, "failed to update titles status to failed")
+// dueCrawlers picks crawlers whose schedule has come and moves their next crawl time forward
+func (s *service) dueCrawlers(nextCrawl map[string]time.Time, now time.Time) []factory.Source {
+	due := make([]factory.Source, 0, len(s.titlesSources))
+	for _, src := range s.titlesSources {
+		if next, ok := nextCrawl[src.Name]; ok && now.Before(next) {
+			continue
 		}
+		nextCrawl[src.Name] = now.Add(src.CrawlEvery)
+		due = append(due, src)
+	}
+	return due
+}
 
+func setValues(s set.Set[uuid.UUID]) []uuid.UUID {
+	values := make([]uuid.UUID, 0, s.Size())
+	for v := range s.Iterator() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// linkTitlesCoins stores coins tagged by the source, titles are matched by hash, since unique insert skips duplicates
+func (s *service) linkTitlesCoins(ctx context.Context, titlesBatch []model.Title) error {
+	hashCoins, coins := taggedCoins(titlesBatch)
+	if len(hashCoins) == 0 {
 		return nil
-	})
+	}
+
+	hashes := make([]string, 0, len(hashCoins))
+	for h := range hashCoins {
+		hashes = append(hashes, h)
+	}
+
+	storedTitles, err := s.dataProvider.TitlesProvider().ByHashes(hashes).Select(ctx)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to select titles by hashes")
+	}
+
+	if err := s.dataProvider.CoinsProvider().UpsertCoinsBatch(ctx, coins); err != nil {
+		return errors.Wrap(err, "failed to upsert batch of coins")
+	}
+
+	if err := s.dataProvider.TitlesCoinsProvider().InsertUniqueBatch(ctx, titleCoinLinks(storedTitles, hashCoins)); err != nil {
+		return errors.Wrap(err, "failed to insert batch of titles-coins")
+	}
+	return nil
 }
 
-func mapFilter(s set.Set[uuid.UUID], rawNews []model.RawNews) []uuid.UUID {
-	return iteration.Map(
-		iteration.Filter(rawNews,
-			func(news model.RawNews) bool {
-				return s.Has(news.TitleID)
-			},
-		),
-		func(t model.RawNews) uuid.UUID {
-			return t.TitleID
-		},
-	)
+// taggedCoins coins of the titles by title hash, and all of them deduplicated by code
+func taggedCoins(titlesBatch []model.Title) (map[string][]model.Coin, []model.Coin) {
+	// hash : []coins
+	hashCoins := make(map[string][]model.Coin)
+	coinsSet := make(map[string]model.Coin)
+	for _, t := range titlesBatch {
+		if len(t.Coins) == 0 {
+			continue
+		}
+		hashCoins[convert.FromPtr(t.Hash)] = t.Coins
+		for _, c := range t.Coins {
+			coinsSet[c.Code] = c
+		}
+	}
+
+	coins := make([]model.Coin, 0, len(coinsSet))
+	for _, c := range coinsSet {
+		coins = append(coins, c)
+	}
+	return hashCoins, coins
+}
+
+// titleCoinLinks titles_coins rows of the stored titles
+func titleCoinLinks(storedTitles []model.Title, hashCoins map[string][]model.Coin) []model.TitleCoin {
+	titlesCoinsBatch := make([]model.TitleCoin, 0, len(storedTitles))
+	for _, t := range storedTitles {
+		for _, c := range hashCoins[convert.FromPtr(t.Hash)] {
+			titlesCoinsBatch = append(titlesCoinsBatch, model.TitleCoin{
+				TitleID: t.ID,
+				Code:    c.Code,
+			})
+		}
+	}
+	return titlesCoinsBatch
+}
+
+// resolveReleaseDates replaces release dates listed by the sources with the publish time from the article page
+func (s *service) resolveReleaseDates(ctx context.Context, rawNews []model.RawNews) error {
+	for _, r := range rawNews {
+		if r.Meta == nil || r.Meta.PublishedAt == nil {
+			continue
+		}
+
+		if _, err := s.dataProvider.TitlesProvider().ByIDs([]uuid.UUID{r.TitleID}).Update(ctx, model.UpdateTitleParams{
+			ReleaseDate: r.Meta.PublishedAt,
+		}); err != nil {
+			return errors.Wrap(err, "failed to update title release date")
+		}
+	}
+	return nil
 }
 
 func (s *service) updateStatusForProcessed(ctx context.Context, processedIDs []uuid.UUID, status string) error {
+	if len(processedIDs) == 0 {
+		return nil
+	}
+
 	if _, err := s.dataProvider.TitlesProvider().ByIDs(processedIDs).Update(ctx, model.UpdateTitleParams{
 		Status: convert.ToPtr(status),
 	}); err != nil {
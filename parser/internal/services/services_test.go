@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"common/convert"
+	"common/data/model"
+)
+
+func TestTitleCoinLinks(t *testing.T) {
+	btc := model.Coin{Code: "BTC", Title: "Bitcoin", Slug: "bitcoin"}
+	eth := model.Coin{Code: "ETH", Title: "Ethereum", Slug: "ethereum"}
+
+	titlesBatch := []model.Title{
+		{Hash: convert.ToPtr("a"), Coins: []model.Coin{btc}},
+		{Hash: convert.ToPtr("b"), Coins: []model.Coin{btc, eth}},
+		{Hash: convert.ToPtr("c")},
+	}
+
+	hashCoins, coins := taggedCoins(titlesBatch)
+	require.Len(t, hashCoins, 2, "titles without coins are not linked")
+	require.ElementsMatch(t, []model.Coin{btc, eth}, coins)
+
+	// the stored titles are selected back by the hashes of the batch
+	a, b := uuid.New(), uuid.New()
+	stored := []model.Title{
+		{ID: a, Hash: convert.ToPtr("a")},
+		{ID: b, Hash: convert.ToPtr("b")},
+		{ID: uuid.New(), Hash: convert.ToPtr("c")},
+	}
+
+	require.ElementsMatch(t, []model.TitleCoin{
+		{TitleID: a, Code: "BTC"},
+		{TitleID: b, Code: "BTC"},
+		{TitleID: b, Code: "ETH"},
+	}, titleCoinLinks(stored, hashCoins))
+}
@@ -0,0 +1,78 @@
+package sitemap_crawler
+
+import (
+	"strings"
+	"time"
+
+	"common/convert"
+	"common/data/model"
+	"common/hash"
+	"parser/internal/services/crawler"
+	"parser/internal/services/metadata"
+)
+
+var _ crawler.ParsedBody = body{}
+
+// rawSitemap covers both <urlset> and <sitemapindex> documents
+type rawSitemap struct {
+	URLs     []sitemapURL `xml:"url"`
+	Sitemaps []sitemapRef `xml:"sitemap"`
+}
+
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+	// News Google News sitemap extension
+	News *sitemapNews `xml:"http://www.google.com/schemas/sitemap-news/0.9 news"`
+}
+
+type sitemapNews struct {
+	Title           string `xml:"title"`
+	PublicationDate string `xml:"publication_date"`
+}
+
+type body struct {
+	title       string
+	url         string
+	releaseDate *time.Time
+	source      string
+}
+
+func (b body) ToModel() any {
+	return model.Title{
+		Title:       &b.title,
+		Hash:        convert.ToPtr(hash.Hash(b.title, b.url)),
+		URL:         &b.url,
+		ReleaseDate: b.releaseDate,
+		Status:      convert.ToPtr(model.StatusPending),
+		Source:      &b.source,
+		Kind:        convert.ToPtr(model.KindNews),
+	}
+}
+
+// releaseDate news publication date is preferred, lastmod also changes on every edit of the article
+func (u sitemapURL) releaseDate() *time.Time {
+	if u.News != nil {
+		if t, ok := metadata.ParseTimestamp(u.News.PublicationDate); ok {
+			return &t
+		}
+	}
+	if t, ok := metadata.ParseTimestamp(u.LastMod); ok {
+		return &t
+	}
+	return nil
+}
+
+// title only news sitemaps have titles, the ones guessed from the url slug would not match the real title of the article,
+// so the same article crawled from another source would be stored twice
+func (u sitemapURL) title() string {
+	if u.News == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(u.News.Title), " ")
+}
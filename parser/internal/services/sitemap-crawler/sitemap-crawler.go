@@ -0,0 +1,281 @@
+package sitemap_crawler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html/charset"
+
+	"common"
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/crawler"
+	"parser/internal/services/metadata"
+)
+
+const Sitemap = "sitemap"
+
+const (
+	defaultWindow      = 48 * time.Hour
+	defaultMaxSitemaps = 10
+	// maxDepth sitemap indexes are not supposed to be nested, but some sites do that
+	maxDepth = 2
+)
+
+// SitemapCrawler reads titles of the recent articles from the news sitemap, sitemap indexes are followed
+type SitemapCrawler struct {
+	log *logrus.Entry
+
+	url    string
+	source string
+
+	urlPatterns []*regexp.Regexp
+	window      time.Duration
+	maxSitemaps int
+
+	conn connector.Connector
+	now  func() time.Time
+}
+
+// NewCrawler sitemap keys point to the sitemap definition, e.g. [sitemap, sites, decrypt]
+func NewCrawler(cfg config.Config, conn connector.Connector, sitemapKeys ...string) crawler.Crawler {
+	window := defaultWindow
+	if raw := cfg.OptionalCredentials(config.CredentialsPath(sitemapKeys, "window")...); raw != "" {
+		w, err := time.ParseDuration(raw)
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to parse sitemap window: %s", raw))
+		}
+		window = w
+	}
+
+	maxSitemaps := defaultMaxSitemaps
+	if raw := cfg.OptionalCredentials(config.CredentialsPath(sitemapKeys, "max_sitemaps")...); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to parse max sitemaps: %s", raw))
+		}
+		maxSitemaps = n
+	}
+
+	rawPatterns := cfg.CredentialsList(config.CredentialsPath(sitemapKeys, "url_patterns")...)
+	urlPatterns := make([]*regexp.Regexp, len(rawPatterns))
+	for i, p := range rawPatterns {
+		r, err := regexp.Compile(p)
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to compile url pattern: %s", p))
+		}
+		urlPatterns[i] = r
+	}
+
+	return &SitemapCrawler{
+		log: cfg.Logging().WithField("service", "[SITEMAP-CRAWLER]").WithField("sitemap", sitemapKeys),
+
+		url:    cfg.Credentials(config.CredentialsPath(sitemapKeys, "url")...),
+		source: cfg.Credentials(config.CredentialsPath(sitemapKeys, "source")...),
+
+		urlPatterns: urlPatterns,
+		window:      window,
+		maxSitemaps: maxSitemaps,
+
+		conn: conn,
+		now:  common.CurrentTimestamp,
+	}
+}
+
+func (c SitemapCrawler) Crawl(ctx context.Context) ([]crawler.ParsedBody, int, error) {
+	since := c.now().Add(-c.window)
+
+	// the root is usually an index, an unchanged index may still list sitemaps changed since the last crawl,
+	// so it is always fetched in full
+	root, statusCode, err := c.fetch(ctx, c.url, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, statusCode, nil
+	}
+
+	fetched := 1
+	urls, err := c.follow(ctx, root, since, 1, &fetched)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seen := make(map[string]bool, len(urls))
+	bodies := make([]crawler.ParsedBody, 0, len(urls))
+	for _, u := range urls {
+		b, ok := c.parseURL(u, since)
+		if !ok || seen[b.url] {
+			continue
+		}
+		seen[b.url] = true
+		bodies = append(bodies, b)
+	}
+
+	c.log.Debugf("Parsed %d of %d sitemap urls from %d sitemaps", len(bodies), len(urls), fetched)
+	return bodies, http.StatusOK, nil
+}
+
+// follow collects urls of the sitemap and of the recently modified sitemaps it lists, newest first
+func (c SitemapCrawler) follow(ctx context.Context, sitemap *rawSitemap, since time.Time, depth int, fetched *int) ([]sitemapURL, error) {
+	urls := sitemap.URLs
+	if len(sitemap.Sitemaps) == 0 || depth > maxDepth {
+		return urls, nil
+	}
+
+	refs := recentSitemaps(sitemap.Sitemaps, since)
+	for _, ref := range refs {
+		if *fetched >= c.maxSitemaps {
+			c.log.WithField("max-sitemaps", c.maxSitemaps).Debug("Sitemaps limit reached, skipping the rest...")
+			break
+		}
+		*fetched++
+
+		// sitemaps listed by an index are urlsets by the protocol, the unchanged ones have nothing new
+		child, statusCode, err := c.fetch(ctx, strings.TrimSpace(ref.Loc), true)
+		if err != nil {
+			return nil, err
+		}
+		if statusCode == http.StatusNotModified {
+			c.log.WithField("sitemap", ref.Loc).Debug("Not modified since the last crawl...")
+			continue
+		}
+		if statusCode != http.StatusOK {
+			c.log.WithFields(logrus.Fields{
+				"sitemap":     ref.Loc,
+				"status-code": statusCode,
+			}).Warn("failed to fetch sitemap...")
+			continue
+		}
+
+		childURLs, err := c.follow(ctx, child, since, depth+1, fetched)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, childURLs...)
+	}
+	return urls, nil
+}
+
+// fetch conditional sitemap is fetched only if modified since the last crawl, 304 is returned otherwise
+func (c SitemapCrawler) fetch(ctx context.Context, sitemapURL string, conditional bool) (*rawSitemap, int, error) {
+	sitemapBody, statusCode, err := c.conn.Poll(ctx, connector.PollParams{
+		Url:         sitemapURL,
+		Conditional: conditional,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to poll sitemap %s", sitemapURL)
+	}
+	if statusCode != http.StatusOK {
+		return nil, statusCode, nil
+	}
+
+	reader, err := decompress(sitemapBody)
+	if err != nil {
+		return nil, statusCode, errors.Wrapf(err, "failed to decompress sitemap %s", sitemapURL)
+	}
+
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var sitemap rawSitemap
+	if err := decoder.Decode(&sitemap); err != nil {
+		return nil, statusCode, errors.Wrapf(err, "failed to decode sitemap %s", sitemapURL)
+	}
+	return &sitemap, statusCode, nil
+}
+
+// parseURL drops urls not matching the patterns and the ones published before the window,
+// urls without any date are dropped as well, since there is no telling new articles from the archive,
+// and so are the urls without the news title
+func (c SitemapCrawler) parseURL(u sitemapURL, since time.Time) (body, bool) {
+	loc := strings.TrimSpace(u.Loc)
+	if loc == "" || !c.allowed(loc) {
+		return body{}, false
+	}
+
+	releaseDate := u.releaseDate()
+	if releaseDate == nil || releaseDate.Before(since) {
+		return body{}, false
+	}
+
+	title := u.title()
+	if title == "" {
+		return body{}, false
+	}
+
+	return body{
+		title:       title,
+		url:         loc,
+		releaseDate: releaseDate,
+		source:      c.source,
+	}, true
+}
+
+// allowed url is allowed if it matches any of the patterns, everything is allowed if there are no patterns
+func (c SitemapCrawler) allowed(url string) bool {
+	if len(c.urlPatterns) == 0 {
+		return true
+	}
+	for _, p := range c.urlPatterns {
+		if p.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// recentSitemaps sitemaps modified within the window, newest first, the ones without lastmod go last
+func recentSitemaps(refs []sitemapRef, since time.Time) []sitemapRef {
+	type datedRef struct {
+		sitemapRef
+		lastMod *time.Time
+	}
+
+	dated := make([]datedRef, 0, len(refs))
+	for _, ref := range refs {
+		d := datedRef{sitemapRef: ref}
+		if t, ok := metadata.ParseTimestamp(ref.LastMod); ok {
+			if t.Before(since) {
+				continue
+			}
+			d.lastMod = &t
+		}
+		dated = append(dated, d)
+	}
+
+	sort.SliceStable(dated, func(i, j int) bool {
+		li, lj := dated[i].lastMod, dated[j].lastMod
+		if li == nil || lj == nil {
+			return li != nil
+		}
+		return li.After(*lj)
+	})
+
+	recent := make([]sitemapRef, 0, len(dated))
+	for _, d := range dated {
+		recent = append(recent, d.sitemapRef)
+	}
+	return recent
+}
+
+// decompress sitemaps are often served as .xml.gz without Content-Encoding, gzip is detected by its magic bytes
+func decompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(2)
+	if err != nil || magic[0] != 0x1f || magic[1] != 0x8b {
+		return buffered, nil
+	}
+	return gzip.NewReader(buffered)
+}
@@ -0,0 +1,122 @@
+package sitemap_crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"common/data/model"
+	"parser/internal/services/connector"
+	"parser/internal/testutil"
+)
+
+const testConfig = `
+crawlers:
+  - name: decrypt
+    type: sitemap
+    credentials: [ sitemap, sites, decrypt ]
+service_providers:
+  services:
+    sitemap:
+      sites:
+        decrypt:
+          url: https://decrypt.co/sitemap_index.xml
+          source: decrypt
+          window: 72h
+          url_patterns: [ '^https://decrypt\.co/\d+/' ]
+`
+
+func TestCrawlSitemapIndex(t *testing.T) {
+	c, _ := testutil.Crawler(t, testConfig, "decrypt", NewCrawler, "sitemap", "sites", "decrypt")
+	// archive sitemap of 2010 is out of the window, so it is never fetched
+	c.(*SitemapCrawler).now = func() time.Time {
+		return time.Date(2023, time.October, 17, 12, 0, 0, 0, time.UTC)
+	}
+
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	titles := make(map[string]model.Title, len(bodies))
+	for _, b := range bodies {
+		title := b.ToModel().(model.Title)
+		titles[*title.URL] = title
+	}
+	require.Len(t, bodies, 2)
+	require.Len(t, titles, 2, "expected unique recent article titles")
+
+	news, ok := titles["https://decrypt.co/201234/bitcoin-etf-decision-delayed-again"]
+	require.True(t, ok, "expected news sitemap article")
+	require.Equal(t, "Bitcoin ETF Decision Delayed Again", *news.Title)
+	require.NotNil(t, news.ReleaseDate)
+	require.True(t, news.ReleaseDate.Equal(time.Date(2023, time.October, 17, 8, 30, 0, 0, time.UTC)),
+		"unexpected publication date %s", news.ReleaseDate)
+
+	// gzipped sitemap mixes news and plain urls, the ones without the news title are skipped
+	post, ok := titles["https://decrypt.co/201200/ethereum-developers-schedule-next-upgrade/"]
+	require.True(t, ok, "expected gzipped sitemap article")
+	require.Equal(t, "Ethereum Developers Schedule Next Upgrade", *post.Title)
+	require.Equal(t, "decrypt", *post.Source)
+	require.NotContains(t, titles, "https://decrypt.co/201210/solana-outage-explained")
+}
+
+// conditionalSite answers 304 to the requests carrying the etag of the page, the conditional requests are recorded
+type conditionalSite struct {
+	pages       map[string]string
+	conditional map[string]bool
+}
+
+func (s *conditionalSite) RoundTrip(req *http.Request) (*http.Response, error) {
+	page, ok := s.pages[req.URL.Path]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+
+	etag := `"` + req.URL.Path + `"`
+	if req.Header.Get("If-None-Match") == etag {
+		s.conditional[req.URL.Path] = true
+		return &http.Response{StatusCode: http.StatusNotModified, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": []string{etag}},
+		Body:       io.NopCloser(strings.NewReader(page)),
+		Request:    req,
+	}, nil
+}
+
+func TestCrawlUnchangedIndex(t *testing.T) {
+	cfg := testutil.Config(t, testConfig)
+	site := &conditionalSite{
+		pages: map[string]string{
+			"/sitemap_index.xml": `<sitemapindex><sitemap><loc>https://decrypt.co/news-sitemap.xml</loc></sitemap></sitemapindex>`,
+			"/news-sitemap.xml": `<urlset xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">` +
+				`<url><loc>https://decrypt.co/201234/bitcoin-etf</loc><lastmod>2023-10-17T08:30:00Z</lastmod>` +
+				`<news:news><news:title>Bitcoin ETF</news:title></news:news></url></urlset>`,
+		},
+		conditional: make(map[string]bool),
+	}
+	c := NewCrawler(cfg, connector.New(cfg, connector.WithTransport(site)), "sitemap", "sites", "decrypt").(*SitemapCrawler)
+	c.now = func() time.Time {
+		return time.Date(2023, time.October, 17, 12, 0, 0, 0, time.UTC)
+	}
+
+	bodies, statusCode, err := c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, bodies, 1)
+
+	bodies, statusCode, err = c.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Empty(t, bodies, "expected no titles of the unchanged sitemap")
+
+	// the index may list changed sitemaps even if it is unchanged itself
+	require.False(t, site.conditional["/sitemap_index.xml"], "expected the index to be fetched in full")
+	require.True(t, site.conditional["/news-sitemap.xml"], "expected the sitemap to be fetched conditionally")
+}
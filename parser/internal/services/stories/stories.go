@@ -0,0 +1,117 @@
+package stories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"common"
+	"common/convert"
+	"common/data"
+	"common/data/model"
+	"common/data/store"
+	"common/simhash"
+	"parser/internal/config"
+)
+
+// Grouper clusters near-duplicate titles syndicated by different sources into stories
+type Grouper interface {
+	// Group links titles to stories and returns raw news of canonical titles only,
+	// alternates stay linked to their story, but their bodies are dropped
+	Group(ctx context.Context, titles []model.Title, rawNews []model.RawNews) ([]model.RawNews, error)
+}
+
+type grouper struct {
+	cfg config.Config
+	log *logrus.Entry
+
+	dataProvider store.DataProvider
+}
+
+func New(cfg config.Config) Grouper {
+	return &grouper{
+		cfg: cfg,
+		log: cfg.Logging().WithField("service", "[STORIES]"),
+
+		dataProvider: store.New(cfg),
+	}
+}
+
+func (g *grouper) Group(ctx context.Context, titles []model.Title, rawNews []model.RawNews) ([]model.RawNews, error) {
+	if len(rawNews) == 0 {
+		return rawNews, nil
+	}
+
+	candidates, err := g.dataProvider.StoriesProvider().
+		CreatedAfter(common.CurrentTimestamp().Add(-g.cfg.StoryWindow())).
+		Select(ctx)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		return nil, errors.Wrap(err, "failed to select recent stories")
+	}
+
+	titlesByID := make(map[uuid.UUID]model.Title, len(titles))
+	for _, t := range titles {
+		titlesByID[t.ID] = t
+	}
+
+	// story id : titles ids
+	members := make(map[uuid.UUID][]uuid.UUID)
+	canonical := make([]model.RawNews, 0, len(rawNews))
+	for _, r := range rawNews {
+		title := titlesByID[r.TitleID]
+		fingerprint := simhash.Fingerprint(convert.FromPtr(title.Title) + "\n" + convert.FromPtr(r.Body))
+
+		if story, ok := g.closest(candidates, fingerprint); ok {
+			members[story.ID] = append(members[story.ID], r.TitleID)
+			// title could have been grouped already by the attempt that failed after that
+			if story.CanonicalTitleID == r.TitleID {
+				canonical = append(canonical, r)
+				continue
+			}
+
+			g.log.WithFields(logrus.Fields{
+				"title-url": convert.FromPtr(title.URL),
+				"story-id":  story.ID,
+			}).Debug("title is a near-duplicate of existing story...")
+			continue
+		}
+
+		story, err := g.dataProvider.StoriesProvider().Insert(ctx, model.Story{
+			CanonicalTitleID: r.TitleID,
+			Simhash:          int64(fingerprint),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to insert story")
+		}
+
+		candidates = append(candidates, *story)
+		members[story.ID] = append(members[story.ID], r.TitleID)
+		canonical = append(canonical, r)
+	}
+
+	for storyID, titleIDs := range members {
+		if _, err := g.dataProvider.TitlesProvider().ByIDs(titleIDs).Update(ctx, model.UpdateTitleParams{
+			StoryID: convert.ToPtr(storyID),
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to link titles to story")
+		}
+	}
+
+	return canonical, nil
+}
+
+// closest story within the configured distance from the fingerprint
+func (g *grouper) closest(candidates []model.Story, fingerprint uint64) (model.Story, bool) {
+	var (
+		best     model.Story
+		bestDist = g.cfg.StoryMaxDistance() + 1
+	)
+	for _, c := range candidates {
+		if d := simhash.Distance(uint64(c.Simhash), fingerprint); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best, bestDist <= g.cfg.StoryMaxDistance()
+}
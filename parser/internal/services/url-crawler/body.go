@@ -4,16 +4,27 @@ import (
 	"github.com/google/uuid"
 
 	"common/data/model"
+	"parser/internal/services/metadata"
 )
 
 type body struct {
-	titleID uuid.UUID
-	text    string
+	titleID  uuid.UUID
+	text     string
+	meta     metadata.Metadata
+	language string
 }
 
 func (b body) ToModel() any {
 	return model.RawNews{
 		TitleID: b.titleID,
 		Body:    &b.text,
+		// always set, so that batch insert has the same columns for every row
+		Meta: &model.RawNewsMeta{
+			Images:       b.meta.Images,
+			Author:       b.meta.Author,
+			CanonicalURL: b.meta.CanonicalURL,
+			PublishedAt:  b.meta.PublishedAt,
+		},
+		Language: &b.language,
 	}
 }
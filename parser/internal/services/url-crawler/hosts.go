@@ -0,0 +1,106 @@
+package url_crawler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// hosts per-host politeness: concurrency cap, rate limit and pause requested by the host via Retry-After
+type hosts struct {
+	mu sync.Mutex
+
+	perHostLimit int
+	rateLimit    rate.Limit
+
+	states map[string]*hostState
+}
+
+type hostState struct {
+	slots chan struct{}
+	rl    *rate.Limiter
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func newHosts(perHostLimit int, rps float64) *hosts {
+	return &hosts{
+		perHostLimit: perHostLimit,
+		rateLimit:    rate.Limit(rps),
+		states:       make(map[string]*hostState),
+	}
+}
+
+func (h *hosts) state(host string) *hostState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.states[host]
+	if !ok {
+		s = &hostState{
+			slots: make(chan struct{}, h.perHostLimit),
+			rl:    rate.NewLimiter(h.rateLimit, 1),
+		}
+		h.states[host] = s
+	}
+	return s
+}
+
+// acquire blocks until the host has a free slot, is not paused and its rate limit allows the request
+func (h *hosts) acquire(ctx context.Context, host string) (release func(), err error) {
+	s := h.state(host)
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "failed to wait for the host slot")
+	}
+	release = func() { <-s.slots }
+
+	if err := s.waitPause(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	if err := s.rl.Wait(ctx); err != nil {
+		release()
+		return nil, errors.Wrap(err, "failed to wait for the host rate limiter")
+	}
+	return release, nil
+}
+
+// pause postpones all the requests to the host, e.g. after 429 with Retry-After
+func (h *hosts) pause(host string, d time.Duration) {
+	s := h.state(host)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+}
+
+func (s *hostState) waitPause(ctx context.Context) error {
+	s.mu.Lock()
+	wait := time.Until(s.pausedUntil)
+	s.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to wait for the host pause")
+	}
+}
@@ -3,8 +3,11 @@ package url_crawler
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
-	"strings"
+	"net/url"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -13,108 +16,206 @@ import (
 	"common/convert"
 	"common/data/model"
 	"common/data/store"
+	"common/math"
 	"parser/internal/config"
 	"parser/internal/services/connector"
 	"parser/internal/services/crawler"
+	"parser/internal/services/extractor"
+	"parser/internal/services/language"
+	"parser/internal/services/metadata"
+	"parser/internal/services/normalizer"
 )
 
+// maxRetryAfter hosts asking to come back later than that are treated as failed for this run
+const maxRetryAfter = 2 * time.Minute
+
 type UrlCrawler struct {
 	log *logrus.Entry
 
-	conn connector.Connector
+	conn       connector.Connector
+	extractors extractor.Registry
+	normalizer normalizer.Normalizer
+	hosts      *hosts
+
+	workers        int
+	requestTimeout time.Duration
+	maxRetries     int
 
 	dataProvider store.DataProvider
 }
 
-func NewCrawler(cfg config.Config) crawler.MultiCrawler[model.Title] {
+// NewCrawler connector options are mostly for tests, e.g. to replay recorded pages
+func NewCrawler(cfg config.Config, opts ...connector.Option) crawler.MultiCrawler[model.Title] {
 	return UrlCrawler{
-		log:          cfg.Logging().WithField("service", "[URL-CRAWLER]"),
-		conn:         connector.New(cfg),
+		log:        cfg.Logging().WithField("service", "[URL-CRAWLER]"),
+		conn:       connector.New(cfg, opts...),
+		extractors: extractor.NewRegistry(cfg),
+		normalizer: normalizer.New(cfg),
+		hosts:      newHosts(cfg.PerHostLimit(), cfg.HostRateLimit()),
+
+		workers:        cfg.Workers(),
+		requestTimeout: cfg.RequestTimeout(),
+		maxRetries:     cfg.MaxRetries(),
+
 		dataProvider: store.New(cfg),
 	}
 }
 
-func hasMatchingID(attrs []html.Attribute) bool {
-	for _, attr := range attrs {
-		if attr.Key == "id" && strings.HasPrefix(attr.Val, "article") {
-			return true
-		}
+func (u UrlCrawler) Crawl(ctx context.Context, pendingTitles []model.Title) ([]crawler.ParsedBody, []int, []error) {
+	// index-aligned with pendingTitles, failed entries have nil body
+	outBodies := make([]crawler.ParsedBody, len(pendingTitles))
+	statusCodes := make([]int, len(pendingTitles))
+	errs := make([]error, len(pendingTitles))
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < math.Min(u.workers, len(pendingTitles)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				outBodies[i], statusCodes[i], errs[i] = u.crawl(ctx, pendingTitles[i])
+			}
+		}()
 	}
-	return false
-}
 
-func extractArticle(doc *html.Node) (*html.Node, error) {
-	var body *html.Node
-	var htmlCrawler func(*html.Node)
-	htmlCrawler = func(node *html.Node) {
-		if node.Type == html.ElementNode && node.Data == "article" && hasMatchingID(node.Attr) {
-			body = node
-			return
-		}
-		for child := node.FirstChild; child != nil; child = child.NextSibling {
-			htmlCrawler(child)
-		}
+	for _, i := range interleaveByHost(pendingTitles) {
+		jobs <- i
 	}
-	htmlCrawler(doc)
-	if body != nil {
-		return body, nil
-	}
-	return nil, errors.New("Missing <article> in the node tree")
-}
+	close(jobs)
+	wg.Wait()
 
-func collectText(n *html.Node, buf *bytes.Buffer) {
-	if n.Type == html.TextNode {
-		buf.WriteString(n.Data)
-	}
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		collectText(c, buf)
-	}
+	return outBodies, statusCodes, errs
 }
 
-func (u UrlCrawler) Crawl(ctx context.Context, pendingTitles []model.Title) ([]crawler.ParsedBody, []int, []error) {
+func (u UrlCrawler) crawl(ctx context.Context, t model.Title) (crawler.ParsedBody, int, error) {
+	// community posts are taken from the listing as is, their pages are mostly comments and scripts
+	if convert.FromPtr(t.Kind) == model.KindCommunity {
+		return communityBody(t), http.StatusOK, nil
+	}
 
-	outBodies := make([]crawler.ParsedBody, 0, len(pendingTitles))
-	statusCodes := make([]int, 0, len(pendingTitles))
-	errs := make([]error, 0, len(pendingTitles))
+	pageURL := convert.FromPtr(t.URL)
 
-	for _, t := range pendingTitles {
-		respBody, statusCode, err := u.conn.Poll(ctx, connector.PollParams{
-			Url: convert.FromPtr(t.URL),
-		})
+	parsedURL, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to parse url %s", pageURL)
+	}
 
+	for attempt := 0; ; attempt++ {
+		rawBody, statusCode, header, err := u.fetch(ctx, parsedURL.Host, pageURL)
 		if err != nil {
-			errs = append(errs, errors.Wrapf(err, "failed to poll url %s", convert.FromPtr(t.URL)))
-			statusCodes = append(statusCodes, statusCode)
-			continue
+			return nil, statusCode, errors.Wrapf(err, "failed to poll url %s", pageURL)
+		}
+
+		if (statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable) && attempt < u.maxRetries {
+			if delay, ok := connector.RetryAfter(header, time.Now()); ok && delay <= maxRetryAfter {
+				u.log.WithFields(logrus.Fields{
+					"host":        parsedURL.Host,
+					"retry-after": delay,
+				}).Debug("Host asked to slow down...")
+
+				u.hosts.pause(parsedURL.Host, delay)
+				continue
+			}
 		}
 
 		if statusCode != http.StatusOK {
-			errs = append(errs, nil)
-			statusCodes = append(statusCodes, statusCode)
-			continue
+			return nil, statusCode, nil
 		}
 
-		rawHtml, err := html.Parse(respBody)
+		rawHtml, err := html.Parse(bytes.NewReader(rawBody))
 		if err != nil {
-			errs = append(errs, errors.Wrap(err, "failed to parse response body"))
-			statusCodes = append(statusCodes, statusCode)
-			continue
+			return nil, statusCode, errors.Wrap(err, "failed to parse response body")
 		}
 
-		rawArticle, err := extractArticle(rawHtml)
+		// metadata is read before extraction, since extractors may strip parts of the document
+		meta := metadata.Extract(pageURL, rawHtml)
+
+		rawArticle, err := u.extractors.Extract(pageURL, rawHtml)
 		if err != nil {
-			errs = append(errs, errors.Wrap(err, "failed to extract article from webpage"))
-			statusCodes = append(statusCodes, statusCode)
-			continue
+			return nil, statusCode, errors.Wrap(err, "failed to extract article from webpage")
 		}
 
-		textBuf := bytes.NewBuffer(make([]byte, 0, 1000))
-		collectText(rawArticle, textBuf)
+		text := u.normalizer.Text(rawArticle)
+		return body{
+			text:     text,
+			titleID:  t.ID,
+			meta:     meta,
+			language: language.Detect(text),
+		}, statusCode, nil
+	}
+}
+
+func communityBody(t model.Title) body {
+	text := convert.FromPtr(t.Title)
+	if summary := convert.FromPtr(t.Summary); summary != "" {
+		text += "\n\n" + summary
+	}
+	return body{
+		text:     text,
+		titleID:  t.ID,
+		language: language.Detect(text),
+	}
+}
 
-		outBodies = append(outBodies, body{text: textBuf.String(), titleID: t.ID})
-		statusCodes = append(statusCodes, statusCode)
-		errs = append(errs, nil)
+// fetch reads the whole page within the host slot and request timeout
+func (u UrlCrawler) fetch(ctx context.Context, host, pageURL string) ([]byte, int, http.Header, error) {
+	release, err := u.hosts.acquire(ctx, host)
+	if err != nil {
+		return nil, 0, nil, err
 	}
+	defer release()
 
-	return outBodies, statusCodes, errs
+	reqCtx, cancel := context.WithTimeout(ctx, u.requestTimeout)
+	defer cancel()
+
+	resp, err := u.conn.Fetch(reqCtx, connector.PollParams{
+		Url: pageURL,
+	})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if closer, ok := resp.Body.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, resp.Header, nil
+	}
+
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, errors.Wrap(err, "failed to read response body")
+	}
+	return rawBody, resp.StatusCode, resp.Header, nil
+}
+
+// interleaveByHost orders titles round-robin by host, so workers are not all stuck waiting for the same host
+func interleaveByHost(titles []model.Title) []int {
+	hostOrder := make([]string, 0, len(titles))
+	// host : title indexes
+	byHost := make(map[string][]int)
+	for i, t := range titles {
+		host := convert.FromPtr(t.URL)
+		if u, err := url.Parse(host); err == nil {
+			host = u.Host
+		}
+		if _, ok := byHost[host]; !ok {
+			hostOrder = append(hostOrder, host)
+		}
+		byHost[host] = append(byHost[host], i)
+	}
+
+	order := make([]int, 0, len(titles))
+	for len(order) < len(titles) {
+		for _, host := range hostOrder {
+			if len(byHost[host]) == 0 {
+				continue
+			}
+			order = append(order, byHost[host][0])
+			byHost[host] = byHost[host][1:]
+		}
+	}
+	return order
 }
@@ -0,0 +1,56 @@
+package url_crawler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"common/convert"
+	"common/data/model"
+	"parser/internal/services/connector"
+	"parser/internal/testutil"
+)
+
+func TestCrawlExtractsArticle(t *testing.T) {
+	cfg := testutil.Config(t, "")
+	c := NewCrawler(cfg, connector.WithTransport(testutil.Transport("cointelegraph")))
+
+	titleID := uuid.New()
+	bodies, statusCodes, errs := c.Crawl(context.Background(), []model.Title{
+		{ID: titleID, URL: convert.ToPtr("https://cointelegraph.com/news/bitcoin-etf-decision-delayed-again")},
+		{ID: uuid.New(), URL: convert.ToPtr("https://cointelegraph.com/private/draft")},
+	})
+
+	require.NoError(t, errs[0])
+	require.Equal(t, http.StatusOK, statusCodes[0])
+
+	rawNews, ok := bodies[0].ToModel().(model.RawNews)
+	require.True(t, ok, "expected raw news model, got %T", bodies[0].ToModel())
+	require.Equal(t, titleID, rawNews.TitleID)
+
+	text := convert.FromPtr(rawNews.Body)
+	require.Contains(t, text, "postponed its decision on a spot Bitcoin exchange-traded fund", "expected article text to be extracted")
+	require.NotContains(t, text, "All rights reserved", "expected page chrome to be dropped")
+	paragraphs := strings.Split(text, "\n\n")
+	require.Len(t, paragraphs, 4, "expected heading and 3 paragraphs")
+	require.Equal(t, "Bitcoin ETF decision delayed again", paragraphs[0])
+
+	require.Equal(t, "en", convert.FromPtr(rawNews.Language))
+
+	meta := rawNews.Meta
+	require.NotNil(t, meta, "expected page metadata")
+	require.Len(t, meta.Images, 2, "expected http images only")
+	require.Equal(t, "https://images.cointelegraph.com/images/bitcoin-etf.jpg", meta.Images[0], "expected og:image first")
+	require.Equal(t, "Jane Doe", meta.Author)
+	require.NotNil(t, meta.PublishedAt)
+	require.True(t, meta.PublishedAt.Equal(time.Date(2023, time.October, 17, 6, 30, 0, 0, time.UTC)),
+		"unexpected published at %s", meta.PublishedAt)
+
+	require.Nil(t, bodies[1])
+	require.ErrorIs(t, errs[1], connector.ErrDisallowed, "expected page disallowed by robots.txt to fail")
+}
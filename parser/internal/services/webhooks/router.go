@@ -0,0 +1,32 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/pkg/errors"
+
+	browse_ai_crawler "parser/internal/services/browse-ai-crawler"
+)
+
+func (s *service) setupRouter() error {
+	browseAI, err := browse_ai_crawler.NewWebhookHandler(s.cfg, s.sink)
+	if err != nil {
+		return errors.Wrap(err, "failed to create browse-ai webhook handler")
+	}
+
+	s.router = chi.NewRouter()
+
+	s.router.Use(
+		middleware.RequestID,
+		middleware.Recoverer,
+	)
+
+	s.router.Get("/healthcheck", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	s.router.Method(http.MethodPost, "/webhooks/browse-ai", browseAI)
+	return nil
+}
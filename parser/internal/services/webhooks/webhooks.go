@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"parser/internal/config"
+	browse_ai_crawler "parser/internal/services/browse-ai-crawler"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// Service http server receiving webhooks of the titles sources
+type Service interface {
+	Listen(ctx context.Context) error
+}
+
+type service struct {
+	log    *logrus.Entry
+	cfg    config.Config
+	router chi.Router
+
+	sink browse_ai_crawler.Sink
+}
+
+func New(cfg config.Config, sink browse_ai_crawler.Sink) Service {
+	return &service{
+		cfg:  cfg,
+		log:  cfg.Logging().WithField("service", "[WEBHOOKS]"),
+		sink: sink,
+	}
+}
+
+func (s *service) Listen(ctx context.Context) error {
+	if err := s.setupRouter(); err != nil {
+		return err
+	}
+
+	s.log.WithField("addr", s.cfg.WebhookAddr()).Info("Starting webhooks server...")
+
+	server := &http.Server{
+		Addr:              s.cfg.WebhookAddr(),
+		Handler:           s.router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			s.log.WithError(err).Error("failed to shutdown webhooks server")
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "webhooks server failed")
+	}
+	return nil
+}
@@ -14,7 +14,6 @@ func (w worker) Produce(ctx context.Context, crawlers []crawler.Crawler) {
 				w.log.WithError(err).Fatal("failed to wait for the rate limiter")
 			}
 
-			w.tasksPending.Inc()
 			w.tasksChan <- Task{
 				seq: i,
 				do:  c.Crawl,
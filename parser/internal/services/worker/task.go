@@ -15,8 +15,15 @@ type Task struct {
 	Err        error
 	StatusCode int
 	StatusBody map[string]json.RawMessage
-	duration   time.Duration
-	handleBy   string // worker name
+	// Deferred to be committed once the body is stored, rolled back otherwise
+	Deferred *crawler.Deferred
+	duration time.Duration
+	handleBy string // worker name
+}
+
+// Seq index of the crawler the task was produced from
+func (t Task) Seq() int {
+	return t.seq
 }
 
 func (t Task) String() string {
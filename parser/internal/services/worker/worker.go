@@ -3,10 +3,10 @@ package worker
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"go.uber.org/atomic"
 	"golang.org/x/time/rate"
 
 	"parser/internal/config"
@@ -21,8 +21,8 @@ type Worker interface {
 type worker struct {
 	log *logrus.Entry
 
-	workersNum   int
-	tasksPending *atomic.Int32
+	workersNum int
+	workers    *sync.WaitGroup
 
 	rl          *rate.Limiter
 	tasksChan   chan Task
@@ -35,8 +35,8 @@ func New(workersNum int, cfg config.Config) Worker {
 
 		workersNum: workersNum,
 
-		tasksPending: atomic.NewInt32(0),
-		rl:           rate.NewLimiter(rate.Every(1*time.Second), cfg.RateLimit()),
+		workers: &sync.WaitGroup{},
+		rl:      rate.NewLimiter(rate.Every(1*time.Second), cfg.RateLimit()),
 
 		tasksChan:   make(chan Task, workersNum),
 		resultsChan: make(chan Task, workersNum),
@@ -46,11 +46,19 @@ func New(workersNum int, cfg config.Config) Worker {
 func (w worker) Work(ctx context.Context) []Task {
 	// distribute
 	for i := 0; i < w.workersNum; i++ {
+		w.workers.Add(1)
 		go func(name string) {
+			defer w.workers.Done()
 			w.work(ctx, name)
 		}(fmt.Sprintf("w-%d", i))
 	}
 
+	// results are closed once all the workers are done, that is once the producer has closed the tasks
+	go func() {
+		w.workers.Wait()
+		close(w.resultsChan)
+	}()
+
 	results := make([]Task, 0, 10)
 	for t := range w.resultsChan {
 		results = append(results, t)
@@ -64,12 +72,14 @@ func (w worker) work(ctx context.Context, name string) {
 		w.log.Debugf("Running task: %d", task.seq)
 
 		start := time.Now()
-		body, code, err := task.do(ctx)
+		taskCtx, deferred := crawler.Defer(ctx)
+		body, code, err := task.do(taskCtx)
 		if err != nil {
 			task.Err = err
 		}
 
 		task.Body = body
+		task.Deferred = deferred
 
 		task.StatusCode = code
 		task.handleBy = name
@@ -78,9 +88,5 @@ func (w worker) work(ctx context.Context, name string) {
 		w.log.Debugf("Finishing task: %s", task.String())
 
 		w.resultsChan <- task
-
-		if w.tasksPending.Dec() == 0 {
-			close(w.resultsChan)
-		}
 	}
 }
@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"parser/internal/services/crawler"
+	"parser/internal/testutil"
+)
+
+type instantCrawler struct{}
+
+func (instantCrawler) Crawl(context.Context) ([]crawler.ParsedBody, int, error) {
+	return nil, http.StatusOK, nil
+}
+
+func TestWorkWaitsForRateLimitedTasks(t *testing.T) {
+	cfg := testutil.Config(t, `rate_limit: 1`)
+
+	// the producer waits for the limiter between the tasks, while the workers are done with the previous ones
+	crawlers := []crawler.Crawler{instantCrawler{}, instantCrawler{}, instantCrawler{}}
+
+	wrk := New(1, cfg)
+	wrk.Produce(context.Background(), crawlers)
+	tasks := wrk.Work(context.Background())
+
+	require.Len(t, tasks, len(crawlers))
+	seqs := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		require.NoError(t, task.Err)
+		seqs[task.Seq()] = true
+	}
+	require.Len(t, seqs, len(crawlers))
+}
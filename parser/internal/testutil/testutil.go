@@ -0,0 +1,75 @@
+// Package testutil builds parser config for tests: no database, in-memory redis and recorded http responses.
+package testutil
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jmoiron/sqlx"
+
+	commoncfg "common/config"
+	"parser/internal/config"
+	"parser/internal/services/connector"
+	"parser/internal/services/connector/recorder"
+	"parser/internal/services/crawler"
+)
+
+type commonConfig struct {
+	commoncfg.Logger
+	commoncfg.Runtime
+	commoncfg.KVStorer
+	noDatabase
+}
+
+// templates and translations are not used by the parser
+func (commonConfig) Template(string) string {
+	return ""
+}
+
+func (commonConfig) Localize(word, _ string) string {
+	return word
+}
+
+// noDatabase tests talk to the KV store and http fixtures only
+type noDatabase struct{}
+
+func (noDatabase) DB() *sqlx.DB {
+	return nil
+}
+
+func (noDatabase) Driver() string {
+	return commoncfg.PostgresDriver
+}
+
+// Config parser config from raw yaml, backed by in-memory redis which lives until the end of the test
+func Config(t *testing.T, rawConfig string) config.Config {
+	t.Helper()
+
+	kv := miniredis.RunT(t)
+	return config.NewWithCommon([]byte(rawConfig), commonConfig{
+		Logger:   commoncfg.NewLogger("error"),
+		Runtime:  commoncfg.NewRuntime("test", "test", nil),
+		KVStorer: commoncfg.NewKVStorer(commoncfg.YamlKVStoreConfig{Address: kv.Addr()}),
+	})
+}
+
+// Transport replays fixtures from testdata/<source>, RECORD_FIXTURES=1 records them from the live sites
+func Transport(source string) http.RoundTripper {
+	return recorder.New(recorder.ModeFromEnv(), filepath.Join("testdata", source), nil)
+}
+
+// Connector replaying fixtures of the source
+func Connector(cfg config.Config, source string) connector.Connector {
+	return connector.New(cfg, connector.WithTransport(Transport(source)))
+}
+
+// Crawler of the source built from raw yaml config, replaying fixtures of testdata/<source>
+func Crawler(t *testing.T, rawConfig, source string,
+	newCrawler func(config.Config, connector.Connector, ...string) crawler.Crawler, keys ...string) (crawler.Crawler, config.Config) {
+	t.Helper()
+
+	cfg := Config(t, rawConfig)
+	return newCrawler(cfg, Connector(cfg, source), keys...), cfg
+}
@@ -108,18 +108,9 @@ func (p poster) Post(ctx context.Context) (int, error) {
 				}
 			}
 
-			if media != nil {
-				if m, ok := media.(tgbotapi.PhotoConfig); ok {
-					if _, err := p.bot.Send(m); err != nil {
-						p.log.WithError(err).Error("failed to send media to bot API")
-						continue
-					}
-				} else if m, ok := media.(tgbotapi.MediaGroupConfig); ok {
-					if _, err := p.bot.SendMediaGroup(m); err != nil {
-						p.log.WithError(err).Error("failed to send media to bot API")
-						continue
-					}
-				}
+			// the text is posted already, so media is best effort, otherwise the text would be posted again
+			if err := p.sendMedia(media); err != nil {
+				p.log.WithError(err).Warn("failed to send media to bot API...")
 			}
 
 			successfulIDs = append(successfulIDs, newsChannel.ID)
@@ -136,6 +127,18 @@ func (p poster) Post(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+func (p poster) sendMedia(media tgbotapi.Chattable) error {
+	switch m := media.(type) {
+	case tgbotapi.PhotoConfig:
+		_, err := p.bot.Send(m)
+		return err
+	case tgbotapi.MediaGroupConfig:
+		_, err := p.bot.SendMediaGroup(m)
+		return err
+	}
+	return nil
+}
+
 func (p poster) buildMessage(channelID int64, news model.News, coins []model.Coin) (*tgbotapi.MessageConfig, tgbotapi.Chattable, error) {
 	msg := tgbotapi.NewMessage(channelID, "")
 	msg.ParseMode = tgbotapi.ModeHTML
@@ -143,6 +146,8 @@ func (p poster) buildMessage(channelID int64, news model.News, coins []model.Coi
 	references := strings.Builder{}
 
 	body := convert.FromPtr(news.Media.Text)
+	// source id : url
+	links := make(map[string]string)
 
 	images := make([]any, 0, 5)
 	for _, resource := range news.Media.Resources {
@@ -154,6 +159,8 @@ func (p poster) buildMessage(channelID int64, news model.News, coins []model.Coi
 			if err := json.Unmarshal(resource.Meta, &metaLinks); err != nil {
 				return nil, nil, errors.Wrap(err, "failed to unmarshal media meta")
 			}
+			links[metaLinks.ID] = metaLinks.URL
+			// digests generated before the structured items had the footnotes in the text
 			body = strings.ReplaceAll(body, fmt.Sprintf("[^%s^][%s]", metaLinks.ID, metaLinks.ID),
 				fmt.Sprintf("<a href=\"%s\">[%s]</a>", metaLinks.URL, metaLinks.ID))
 			references.WriteString(fmt.Sprintf("[%s] <a href=\"%s\">%s</a>.\n", metaLinks.ID, metaLinks.URL, metaLinks.Title))
@@ -167,6 +174,10 @@ func (p poster) buildMessage(channelID int64, news model.News, coins []model.Coi
 		}
 	}
 
+	if len(news.Media.Items) > 0 {
+		body = renderItems(news.Media.Items, links)
+	}
+
 	coinsHashTags := strings.Builder{}
 	for _, coin := range coins {
 		coinsHashTags.WriteString(fmt.Sprintf("#%s ", coin.Code))
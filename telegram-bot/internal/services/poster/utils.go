@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+
+	"common/data/model"
 )
 
 func escapeKeepingHTML(text string) string {
@@ -17,6 +19,22 @@ func escapeKeepingHTML(text string) string {
 	return replacer.Replace(text)
 }
 
+// renderItems digest items with their headlines in bold, each item is followed by the links to its sources
+func renderItems(items []model.NewsItem, links map[string]string) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		var b strings.Builder
+		fmt.Fprintf(&b, "<b>%s</b>\n%s", item.Headline, item.Body)
+		for _, id := range item.Sources {
+			if url, ok := links[id]; ok {
+				fmt.Fprintf(&b, " <a href=\"%s\">[%s]</a>", url, id)
+			}
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
 func downloadFile(fileUrl string) ([]byte, error) {
 	//Get the response bytes from the url
 	response, err := http.Get(fileUrl)